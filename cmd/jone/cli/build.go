@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Builds a standalone migration binary",
+	Long: `Compiles the migrations and jone/jonefile.go into a self-contained binary that runs
+without the Go toolchain, for example in a production image. It takes the same commands
+as jone without the migrate: prefix (migrate latest, migrate rollback, migrate list, ...).
+
+Connection settings can be overridden when it runs with JONE_CLIENT, JONE_DB_HOST,
+JONE_DB_PORT, JONE_DB_USER, JONE_DB_PASSWORD, JONE_DB_NAME, JONE_DB_SSLMODE and JONE_ENV.
+Set GOOS and GOARCH to build for another platform.`,
+	Run: buildJone,
+}
+
+func init() {
+	buildCmd.Flags().StringP("output", "o", "bin/migrate", "Path of the binary to write")
+}
+
+func buildJone(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	if err := buildBinary(output); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error building migration binary: %v", err)))
+		os.Exit(1)
+	}
+	fmt.Println(term.GreenText(fmt.Sprintf("✓ Built %s", output)))
+}
+
+// buildBinary compiles the runner to output.
+func buildBinary(output string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	runner, binaryPath, err := prepareBuild(cwd, output)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(filepath.Join(cwd, JoneFolderPath, ".runner"))
+
+	return compileRunner(cwd, runner, binaryPath)
+}
+
+// prepareBuild regenerates the registry of the project in cwd and returns the
+// runner source to compile and the absolute path of the binary to write, whose
+// directory it creates. A relative output is relative to cwd.
+func prepareBuild(cwd, output string) (runner []byte, binaryPath string, err error) {
+	if _, err := os.Stat(filepath.Join(cwd, JoneFolderPath)); os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("jone folder not found, please run jone init first")
+	}
+
+	modulePath := ReadModulePath(cwd)
+	if modulePath == "" {
+		return nil, "", fmt.Errorf("could not read module path. Ensure go.mod exists and contains a valid module declaration")
+	}
+
+	if err := RegenerateRegistry(cwd); err != nil {
+		return nil, "", fmt.Errorf("regenerating registry: %w", err)
+	}
+
+	runner, err = renderRunner(modulePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("generating runner: %w", err)
+	}
+
+	binaryPath = output
+	if !filepath.IsAbs(binaryPath) {
+		binaryPath = filepath.Join(cwd, binaryPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(binaryPath), 0o755); err != nil {
+		return nil, "", fmt.Errorf("creating output directory: %w", err)
+	}
+	return runner, binaryPath, nil
+}
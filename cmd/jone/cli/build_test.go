@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrepareBuild(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":     "module example.com/app\n\ngo 1.21\n",
+		JoneFilePath: "package jone\n",
+		MigrationsPath + "/20260101000000_users/" + UpSQLFile: "CREATE TABLE users (id int);\n",
+	})
+	abs := filepath.Join(t.TempDir(), "out", "migrate")
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"bin/migrate", filepath.Join(root, "bin", "migrate")},
+		{"./dist/../bin/jone-migrate", filepath.Join(root, "bin", "jone-migrate")},
+		{abs, abs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			runner, binaryPath, err := prepareBuild(root, tt.output)
+			if err != nil {
+				t.Fatalf("prepareBuild() error: %v", err)
+			}
+			if binaryPath != tt.want {
+				t.Errorf("binary path = %s, want %s", binaryPath, tt.want)
+			}
+			if info, err := os.Stat(filepath.Dir(binaryPath)); err != nil || !info.IsDir() {
+				t.Errorf("output directory not created: %v", err)
+			}
+			for _, imp := range []string{
+				`"example.com/app/jone/migrations/registry"`,
+				`joneconfig "example.com/app/jone"`,
+				`"` + RuntimePackage + `"`,
+			} {
+				if !strings.Contains(string(runner), imp) {
+					t.Errorf("runner doesn't import %s", imp)
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(root, MigrationsPath, "registry", "registry.go")); err != nil {
+		t.Errorf("registry not regenerated: %v", err)
+	}
+}
+
+func TestPrepareBuild_NotAProject(t *testing.T) {
+	root := t.TempDir()
+	if _, _, err := prepareBuild(root, "bin/migrate"); err == nil || !strings.Contains(err.Error(), "jone init") {
+		t.Errorf("prepareBuild() without a jone folder error = %v", err)
+	}
+
+	writeFiles(t, root, map[string]string{JoneFilePath: "package jone\n"})
+	if _, _, err := prepareBuild(root, "bin/migrate"); err == nil || !strings.Contains(err.Error(), "module path") {
+		t.Errorf("prepareBuild() without go.mod error = %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// noCache is set by --no-cache to build the runner even if a cached one matches.
+var noCache bool
+
+// runnerCacheDir returns the directory holding this project's cached runners,
+// under the user cache directory.
+func runnerCacheDir(cwd string) (string, error) {
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	project := sha256.Sum256([]byte(cwd))
+	return filepath.Join(userCache, "jone", "runners", hex.EncodeToString(project[:8])), nil
+}
+
+// cachedRunner returns the cached runner built from the current sources,
+// building it into cacheDir first if there is none. Runners built from older
+// sources are removed.
+func cachedRunner(cwd, cacheDir string, runner []byte) (string, error) {
+	key, err := runnerCacheKey(cwd, runner)
+	if err != nil {
+		return "", fmt.Errorf("hashing runner sources: %w", err)
+	}
+	binaryPath := filepath.Join(cacheDir, key)
+	if _, err := os.Stat(binaryPath); err == nil {
+		return binaryPath, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating runner cache: %w", err)
+	}
+	// Build under a temporary name so a concurrent run never sees a partial binary
+	tmp, err := os.CreateTemp(cacheDir, key+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("creating runner cache: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := compileRunner(cwd, runner, tmp.Name()); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), binaryPath); err != nil {
+		return "", fmt.Errorf("caching runner: %w", err)
+	}
+	os.Remove(filepath.Join(cwd, JoneFolderPath, ".runner"))
+
+	pruneRunnerCache(cacheDir, key)
+	return binaryPath, nil
+}
+
+// pruneRunnerCache removes the runners in cacheDir other than keep.
+func pruneRunnerCache(cacheDir, keep string) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.Name() == keep || filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil {
+			fmt.Fprintln(os.Stderr, term.YellowText(fmt.Sprintf("Warning: failed to remove old runner: %v", err)))
+		}
+	}
+}
+
+// goVersion returns the version of the Go toolchain that builds the runner in
+// cwd, which go.mod's toolchain line can select. If the go command can't
+// report it, the version jone was built with is used.
+func goVersion(cwd string) string {
+	cmd := exec.Command("go", "env", "GOVERSION")
+	cmd.Dir = cwd
+	out, err := cmd.Output()
+	if err != nil {
+		return runtime.Version()
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// runnerCacheKey hashes everything the runner is built from: the jone and Go
+// versions, the generated runner, go.mod, go.sum, every file under jone/ (the
+// config, migrations and registry) and the Go environment variables that
+// change the build.
+func runnerCacheKey(cwd string, runner []byte) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "jone %s\n", Version)
+	fmt.Fprintf(h, "go %s\n", goVersion(cwd))
+	for _, env := range []string{"GOOS", "GOARCH", "GOFLAGS", "CGO_ENABLED"} {
+		fmt.Fprintf(h, "%s=%s\n", env, os.Getenv(env))
+	}
+	fmt.Fprintf(h, "runner %d\n", len(runner))
+	h.Write(runner)
+
+	hashFile := func(rel string) error {
+		f, err := os.Open(filepath.Join(cwd, rel))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s %d\n", filepath.ToSlash(rel), info.Size())
+		_, err = io.Copy(h, f)
+		return err
+	}
+
+	for _, name := range []string{"go.mod", "go.sum"} {
+		if err := hashFile(name); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	err := filepath.WalkDir(filepath.Join(cwd, JoneFolderPath), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".runner" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(cwd, path)
+		if err != nil {
+			return err
+		}
+		return hashFile(rel)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
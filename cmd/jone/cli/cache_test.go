@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newCacheProject writes a minimal project with a config and one migration.
+func newCacheProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":     "module example.com/app\n\ngo 1.21\n",
+		"go.sum":     "",
+		JoneFilePath: "package jone\n",
+		MigrationsPath + "/20260101000000_users/migration.go": "package m\n",
+	})
+	return root
+}
+
+func TestRunnerCacheKey(t *testing.T) {
+	runner := []byte("package main\n")
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, root string) []byte // Returns the runner source to hash
+		changed bool
+	}{
+		{"nothing", func(t *testing.T, root string) []byte { return runner }, false},
+		{"migration edited", func(t *testing.T, root string) []byte {
+			writeFiles(t, root, map[string]string{MigrationsPath + "/20260101000000_users/migration.go": "package m\n\n// Edited\n"})
+			return runner
+		}, true},
+		{"migration added", func(t *testing.T, root string) []byte {
+			writeFiles(t, root, map[string]string{MigrationsPath + "/20260102000000_posts/up.sql": "CREATE TABLE posts (id int);\n"})
+			return runner
+		}, true},
+		{"jonefile edited", func(t *testing.T, root string) []byte {
+			writeFiles(t, root, map[string]string{JoneFilePath: "package jone\n\n// Edited\n"})
+			return runner
+		}, true},
+		{"go.mod edited", func(t *testing.T, root string) []byte {
+			writeFiles(t, root, map[string]string{"go.mod": "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n"})
+			return runner
+		}, true},
+		{"runner source changed", func(t *testing.T, root string) []byte { return []byte("package main\n\n// Edited\n") }, true},
+		{"jone version changed", func(t *testing.T, root string) []byte {
+			old := Version
+			Version = old + "-next"
+			t.Cleanup(func() { Version = old })
+			return runner
+		}, true},
+		{"runner build directory ignored", func(t *testing.T, root string) []byte {
+			writeFiles(t, root, map[string]string{JoneFolderPath + "/.runner/main.go": "package main\n"})
+			return runner
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newCacheProject(t)
+			before, err := runnerCacheKey(root, runner)
+			if err != nil {
+				t.Fatalf("runnerCacheKey() error: %v", err)
+			}
+			after, err := runnerCacheKey(root, tt.change(t, root))
+			if err != nil {
+				t.Fatalf("runnerCacheKey() error: %v", err)
+			}
+			if changed := after != before; changed != tt.changed {
+				t.Errorf("key changed = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestCachedRunner_UsesCachedBinary(t *testing.T) {
+	root := newCacheProject(t)
+	cacheDir := t.TempDir()
+	runner := []byte("package main\n")
+	key, err := runnerCacheKey(root, runner)
+	if err != nil {
+		t.Fatalf("runnerCacheKey() error: %v", err)
+	}
+	writeFiles(t, cacheDir, map[string]string{key: "binary"})
+
+	// The runner source doesn't compile, so a build would fail
+	path, err := cachedRunner(root, cacheDir, runner)
+	if err != nil {
+		t.Fatalf("cachedRunner() error: %v", err)
+	}
+	if path != filepath.Join(cacheDir, key) {
+		t.Errorf("cachedRunner() = %s, want the cached binary", path)
+	}
+}
+
+func TestPruneRunnerCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	writeFiles(t, cacheDir, map[string]string{
+		"old":             "binary",
+		"older":           "binary",
+		"current":         "binary",
+		"current.123.tmp": "building", // Another run's build in progress
+	})
+
+	pruneRunnerCache(cacheDir, "current")
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"current", "current.123.tmp"}; !slices.Equal(names, want) {
+		t.Errorf("cache holds %v, want %v", names, want)
+	}
+}
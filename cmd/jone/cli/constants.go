@@ -7,8 +7,20 @@ const (
 	JoneFolderPath = "jone"
 	JoneFilePath   = "jone/jonefile.go"
 	MigrationsPath = "jone/migrations"
+	StubsPath      = "jone/stubs" // Project overrides of the migration stubs
 )
 
+// Files of a migration written in SQL
+const (
+	UpSQLFile    = "up.sql"
+	DownSQLFile  = "down.sql"
+	SQLEmbedFile = "embed.go" // Generated; embeds the .sql files
+)
+
+// NoTransactionMarker is a comment line that, among the comments at the top
+// of up.sql, makes the migration run without a transaction.
+const NoTransactionMarker = "-- jone:no-transaction"
+
 // RuntimePackage is the import path for the jone library
 const RuntimePackage = "github.com/Grandbusta/jone"
 
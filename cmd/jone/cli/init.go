@@ -20,7 +20,7 @@ var initCmd = &cobra.Command{
 }
 
 func init() {
-	initCmd.Flags().StringP("db", "d", "postgres", "Database type (postgres, mysql, sqlite)")
+	initCmd.Flags().StringP("db", "d", "postgres", "Database type (postgres, mysql, sqlite, mssql)")
 }
 
 func initJone(cmd *cobra.Command, args []string) {
@@ -40,9 +40,11 @@ func initJone(cmd *cobra.Command, args []string) {
 		database = "mysql"
 	case "sqlite", "sqlite3":
 		database = "sqlite"
+	case "mssql", "sqlserver":
+		database = "mssql"
 	default:
 		fmt.Printf("Unsupported database: %s\n", dbFlag)
-		fmt.Println("Supported databases: postgres, mysql, sqlite")
+		fmt.Println("Supported databases: postgres, mysql, sqlite, mssql")
 		os.Exit(1)
 	}
 
@@ -95,6 +97,8 @@ func getDriverPackage(database string) string {
 		return "github.com/go-sql-driver/mysql"
 	case "sqlite":
 		return "github.com/mattn/go-sqlite3"
+	case "mssql":
+		return "github.com/microsoft/go-mssqldb"
 	default:
 		return ""
 	}
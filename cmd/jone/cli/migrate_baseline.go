@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateBaselineCmd = &cobra.Command{
+	Use:   "migrate:baseline <migration_name>",
+	Short: "Marks migrations up to a given one as applied without running them",
+	Long: `Records every migration up to and including the named one as applied, in a new batch,
+without running their Up functions. Use it to adopt jone on a database whose schema already exists.`,
+	Run: migrateBaseline,
+}
+
+func init() {
+	migrateBaselineCmd.Flags().Bool("dry-run", false, "Show which migrations would be marked without writing anything")
+}
+
+func migrateBaseline(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Please provide the migration name to baseline at")
+		return
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	execParams := RunExecParams{
+		Command: "migrate:baseline",
+		Args:    args[:1],
+		Flags: map[string]any{
+			"dry-run": dryRun,
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error baselining migrations: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -17,6 +17,7 @@ var migrateDownCmd = &cobra.Command{
 
 func init() {
 	migrateDownCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	addOutputFlag(migrateDownCmd)
 }
 
 func migrateDownJone(cmd *cobra.Command, args []string) {
@@ -26,10 +27,11 @@ func migrateDownJone(cmd *cobra.Command, args []string) {
 		Args:    args,
 		Flags: map[string]any{
 			"dry-run": dryRun,
+			"output":  outputFlag(cmd),
 		},
 	}
 	if err := runMigrations(execParams); err != nil {
-		fmt.Println(term.RedText(fmt.Sprintf("Error rolling back migrations: %v", err)))
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error rolling back migrations: %v", err)))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateFakeCmd = &cobra.Command{
+	Use:   "migrate:fake <migration_name>",
+	Short: "Marks a single migration as applied without running it",
+	Long: `Inserts the tracking record of the named migration without running its Up function.
+With --down, removes its record without running Down.`,
+	Run: migrateFake,
+}
+
+func init() {
+	migrateFakeCmd.Flags().Bool("down", false, "Remove the migration's record instead")
+	migrateFakeCmd.Flags().Bool("dry-run", false, "Show what would be marked without writing anything")
+}
+
+func migrateFake(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Please provide the migration name")
+		return
+	}
+
+	down, _ := cmd.Flags().GetBool("down")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	execParams := RunExecParams{
+		Command: "migrate:fake",
+		Args:    args[:1],
+		Flags: map[string]any{
+			"down":    down,
+			"dry-run": dryRun,
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error faking migration: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateFreshCmd = &cobra.Command{
+	Use:   "migrate:fresh",
+	Short: "Drops all tables and runs all migrations",
+	Long: `Drops every table, view and type in the configured schema without running any Down
+function, then runs all migrations. Refuses to run unless Environment in jonefile.go is
+set to a non-production value, or --force is given.`,
+	Run: migrateFresh,
+}
+
+func init() {
+	migrateFreshCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	migrateFreshCmd.Flags().Bool("force", false, "Run even if Environment is unset or production")
+	addOutputFlag(migrateFreshCmd)
+}
+
+func migrateFresh(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+	execParams := RunExecParams{
+		Command: "migrate:fresh",
+		Flags: map[string]any{
+			"dry-run": dryRun,
+			"force":   force,
+			"output":  outputFlag(cmd),
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateHistoryCmd = &cobra.Command{
+	Use:   "migrate:history",
+	Short: "Shows every migration run, including rollbacks and failures",
+	Long: `Prints the migration history log: every up and down run with when it ran, how long it
+took, who ran it, the jone version and whether it succeeded.`,
+	Run: migrateHistory,
+}
+
+func init() {
+	addOutputFlag(migrateHistoryCmd)
+}
+
+func migrateHistory(cmd *cobra.Command, args []string) {
+	execParams := RunExecParams{
+		Command: "migrate:history",
+		Flags: map[string]any{
+			"output": outputFlag(cmd),
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error reading migration history: %v", err)))
+		os.Exit(1)
+	}
+}
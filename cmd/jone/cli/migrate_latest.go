@@ -17,18 +17,26 @@ var migrateLatestCmd = &cobra.Command{
 
 func init() {
 	migrateLatestCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	migrateLatestCmd.Flags().Bool("allow-missing", false, "Run even if applied migrations are missing from the registry")
+	migrateLatestCmd.Flags().Bool("single-transaction", false, "Run all pending migrations in one transaction")
+	addOutputFlag(migrateLatestCmd)
 }
 
 func migrateLatestJone(cmd *cobra.Command, args []string) {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	allowMissing, _ := cmd.Flags().GetBool("allow-missing")
+	singleTransaction, _ := cmd.Flags().GetBool("single-transaction")
 	execParams := RunExecParams{
 		Command: "migrate:latest",
 		Flags: map[string]any{
-			"dry-run": dryRun,
+			"dry-run":            dryRun,
+			"allow-missing":      allowMissing,
+			"single-transaction": singleTransaction,
+			"output":             outputFlag(cmd),
 		},
 	}
 	if err := runMigrations(execParams); err != nil {
-		fmt.Println(term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
 		os.Exit(1)
 	}
 }
@@ -12,16 +12,28 @@ var migrateListCmd = &cobra.Command{
 	Use:     "migrate:list",
 	Aliases: []string{"migrate:status"},
 	Short:   "Lists all migrations with their status (alias: migrate:status)",
-	Long:    `Lists all registered migrations showing which are applied and which are pending.`,
-	Run:     migrateListJone,
+	Long: `Lists all registered migrations showing which are applied and which are pending.
+
+With --check it exits with status 1 when any migration is pending, for use in CI or deploy scripts.`,
+	Run: migrateListJone,
+}
+
+func init() {
+	migrateListCmd.Flags().Bool("check", false, "Exit with status 1 if any migration is pending")
+	addOutputFlag(migrateListCmd)
 }
 
 func migrateListJone(cmd *cobra.Command, args []string) {
+	check, _ := cmd.Flags().GetBool("check")
 	execParams := RunExecParams{
 		Command: "migrate:list",
+		Flags: map[string]any{
+			"check":  check,
+			"output": outputFlag(cmd),
+		},
 	}
 	if err := runMigrations(execParams); err != nil {
-		fmt.Println(term.RedText(fmt.Sprintf("Error listing migrations: %v", err)))
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error listing migrations: %v", err)))
 		os.Exit(1)
 	}
 }
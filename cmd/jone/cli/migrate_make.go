@@ -3,8 +3,10 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
+	"text/template"
 	"time"
 
 	"github.com/Grandbusta/jone/cmd/jone/templates"
@@ -22,8 +24,26 @@ and if it does not exist, it creates it.
 var migrateMakeCmd = &cobra.Command{
 	Use:   "migrate:make",
 	Short: "Creates a new migration",
-	Long:  `Creates a new migration file in the jone/migrations folder`,
-	Run:   migrateMakeJone,
+	Long: `Creates a new migration file in the jone/migrations folder.
+With --create or --table, the migration starts with code that creates or alters
+that table. With --sql, creates up.sql and down.sql instead of migration.go.
+
+Stubs in jone/stubs (migration.stub, migration.create.stub, migration.table.stub)
+replace the built-in ones.`,
+	Run: migrateMakeJone,
+}
+
+func init() {
+	migrateMakeCmd.Flags().Bool("sql", false, "Write the migration as up.sql and down.sql")
+	migrateMakeCmd.Flags().String("create", "", "Scaffold creating this table")
+	migrateMakeCmd.Flags().String("table", "", "Scaffold altering this table")
+}
+
+// makeOptions selects what migrate:make writes.
+type makeOptions struct {
+	sql    bool   // up.sql and down.sql instead of migration.go
+	create string // Table to create
+	table  string // Table to alter
 }
 
 func migrateMakeJone(cmd *cobra.Command, args []string) {
@@ -32,6 +52,19 @@ func migrateMakeJone(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	var opts makeOptions
+	opts.sql, _ = cmd.Flags().GetBool("sql")
+	opts.create, _ = cmd.Flags().GetString("create")
+	opts.table, _ = cmd.Flags().GetString("table")
+	if opts.create != "" && opts.table != "" {
+		fmt.Println("Use only one of --create and --table")
+		return
+	}
+	if opts.sql && (opts.create != "" || opts.table != "") {
+		fmt.Println("--create and --table can't be used with --sql")
+		return
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to get working directory: %v\n", err)
@@ -71,7 +104,7 @@ func migrateMakeJone(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	migrationPath, err := createMigration(cwd, args[0])
+	migrationPath, err := createMigration(cwd, args[0], opts)
 	if err != nil {
 		fmt.Printf("Error creating migration: %v\n", err)
 		return
@@ -85,22 +118,37 @@ func migrateMakeJone(cmd *cobra.Command, args []string) {
 	fmt.Printf("Migration %s created successfully: %s\n", args[0], migrationPath)
 }
 
-func createMigration(cwd string, name string) (migrationPath string, err error) {
+func createMigration(cwd string, name string, opts makeOptions) (migrationPath string, err error) {
 	ts := time.Now().UTC().Format("20060102150405")
 	folderName := fmt.Sprintf("%s_%s", ts, name)
 	folderPath := filepath.Join(cwd, MigrationsPath, folderName)
 
-	if err := os.Mkdir(folderPath, 0755); err != nil {
-		return "", fmt.Errorf("creating migration folder: %w", err)
+	if opts.sql {
+		if err := os.Mkdir(folderPath, 0755); err != nil {
+			return "", fmt.Errorf("creating migration folder: %w", err)
+		}
+		return createSQLMigration(folderPath, folderName)
 	}
 
-	stub, err := templates.RenderMigration(templates.MigrationStubData{
+	// Render the stub first, so a broken stub leaves no migration folder behind
+	stubName, table := templates.StubBlank, ""
+	switch {
+	case opts.create != "":
+		stubName, table = templates.StubCreate, opts.create
+	case opts.table != "":
+		stubName, table = templates.StubTable, opts.table
+	}
+	stub, err := renderStub(cwd, stubName, templates.MigrationStubData{
 		RuntimePackage: RuntimePackage,
+		Table:          table,
 	})
 	if err != nil {
 		return "", fmt.Errorf("rendering migration stub: %w", err)
 	}
 
+	if err := os.Mkdir(folderPath, 0755); err != nil {
+		return "", fmt.Errorf("creating migration folder: %w", err)
+	}
 	migrationFilePath := filepath.Join(folderPath, "migration.go")
 	if err := os.WriteFile(migrationFilePath, stub, 0o644); err != nil {
 		return "", fmt.Errorf("writing migration file: %w", err)
@@ -110,3 +158,43 @@ func createMigration(cwd string, name string) (migrationPath string, err error)
 	relativePath := filepath.Join(MigrationsPath, folderName, "migration.go")
 	return relativePath, nil
 }
+
+// createSQLMigration writes up.sql and down.sql stubs to folderPath. The
+// registry generates the embed.go that goes with them.
+func createSQLMigration(folderPath, folderName string) (string, error) {
+	for file, direction := range map[string]string{UpSQLFile: "Up", DownSQLFile: "Down"} {
+		stub, err := templates.RenderSQLStub(templates.SQLStubData{Name: folderName, Direction: direction})
+		if err != nil {
+			return "", fmt.Errorf("rendering %s stub: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(folderPath, file), stub, 0o644); err != nil {
+			return "", fmt.Errorf("writing %s: %w", file, err)
+		}
+	}
+	return filepath.Join(MigrationsPath, folderName, UpSQLFile), nil
+}
+
+// renderStub renders the named migration stub, using the project's copy in
+// jone/stubs if there is one. It fails if the stub doesn't render to valid Go.
+func renderStub(cwd, name string, data templates.MigrationStubData) ([]byte, error) {
+	tmpl, source := templates.Stubs[name], "built-in "+name
+	content, err := os.ReadFile(filepath.Join(cwd, StubsPath, name))
+	switch {
+	case err == nil:
+		source = filepath.Join(StubsPath, name)
+		if tmpl, err = template.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", source, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	out, err := templates.Render(tmpl, data)
+	if err != nil {
+		return nil, fmt.Errorf("executing %s: %w", source, err)
+	}
+	if _, err := format.Source(out); err != nil {
+		return nil, fmt.Errorf("%s doesn't render to valid Go: %w", source, err)
+	}
+	return out, nil
+}
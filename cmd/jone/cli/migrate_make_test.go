@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/cmd/jone/templates"
+)
+
+func TestRenderStub(t *testing.T) {
+	data := templates.MigrationStubData{RuntimePackage: RuntimePackage, Table: "users"}
+	tests := []struct {
+		name     string
+		stub     string // Built-in stub to render
+		override string // Project copy in jone/stubs, if any
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "built-in create", stub: templates.StubCreate,
+			want: []string{`"` + RuntimePackage + `"`, `s.CreateTable("users"`, `s.DropTable("users")`},
+		},
+		{
+			name: "built-in blank", stub: templates.StubBlank,
+			want: []string{"func Up(s *jone.Schema) {", "func Down(s *jone.Schema) {"},
+		},
+		{
+			name: "project override", stub: templates.StubCreate,
+			override: "package migration\n\nimport \"{{ .RuntimePackage }}\"\n\n// Creates {{ .Table }}\nfunc Up(s *jone.Schema) {}\n\nfunc Down(s *jone.Schema) {}\n",
+			want:     []string{"// Creates users"},
+		},
+		{
+			name: "override that doesn't parse", stub: templates.StubBlank,
+			override: "package migration\n{{ if }}\n",
+			wantErr:  "parsing " + filepath.Join(StubsPath, templates.StubBlank),
+		},
+		{
+			name: "override with an unknown field", stub: templates.StubTable,
+			override: "package migration\n// {{ .Columns }}\n",
+			wantErr:  "executing " + filepath.Join(StubsPath, templates.StubTable),
+		},
+		{
+			name: "override that renders invalid Go", stub: templates.StubBlank,
+			override: "package migration\n\nfunc Up(s *jone.Schema) {\n",
+			wantErr:  "doesn't render to valid Go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.override != "" {
+				writeFiles(t, root, map[string]string{StubsPath + "/" + tt.stub: tt.override})
+			}
+
+			out, err := renderStub(root, tt.stub, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderStub() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderStub() error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("stub doesn't contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateMigration_BrokenStub(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{StubsPath + "/" + templates.StubBlank: "package migration\n\nfunc Up(\n"})
+	if err := os.MkdirAll(filepath.Join(root, MigrationsPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := createMigration(root, "add_users", makeOptions{}); err == nil {
+		t.Fatal("createMigration() with a broken stub succeeded")
+	}
+	entries, err := os.ReadDir(filepath.Join(root, MigrationsPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("createMigration() left %s behind", entries[0].Name())
+	}
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateRefreshCmd = &cobra.Command{
+	Use:   "migrate:refresh",
+	Short: "Rolls back all migrations and runs them again",
+	Long:  `Rolls back every applied migration through its Down function, then runs all migrations.`,
+	Run:   migrateRefresh,
+}
+
+func init() {
+	migrateRefreshCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	addOutputFlag(migrateRefreshCmd)
+}
+
+func migrateRefresh(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	execParams := RunExecParams{
+		Command: "migrate:refresh",
+		Flags: map[string]any{
+			"dry-run": dryRun,
+			"output":  outputFlag(cmd),
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		os.Exit(1)
+	}
+}
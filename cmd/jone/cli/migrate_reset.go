@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateResetCmd = &cobra.Command{
+	Use:   "migrate:reset",
+	Short: "Rolls back all migrations",
+	Long:  `Rolls back every applied migration through its Down function.`,
+	Run:   migrateReset,
+}
+
+func init() {
+	migrateResetCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	addOutputFlag(migrateResetCmd)
+}
+
+func migrateReset(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	execParams := RunExecParams{
+		Command: "migrate:reset",
+		Flags: map[string]any{
+			"dry-run": dryRun,
+			"output":  outputFlag(cmd),
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -16,21 +16,32 @@ var migrateRollbackCmd = &cobra.Command{
 
 func init() {
 	migrateRollbackCmd.Flags().BoolP("all", "a", false, "Rollback all migrations")
+	migrateRollbackCmd.Flags().Int("step", 0, "Rollback the last N migrations, regardless of batch")
+	migrateRollbackCmd.Flags().Int("batch", 0, "Rollback only batch N")
+	migrateRollbackCmd.Flags().Int("to-batch", 0, "Rollback every batch above N")
 	migrateRollbackCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	addOutputFlag(migrateRollbackCmd)
 }
 
 func migrateRollback(cmd *cobra.Command, args []string) {
 	allFlag, _ := cmd.Flags().GetBool("all")
+	step, _ := cmd.Flags().GetInt("step")
+	batch, _ := cmd.Flags().GetInt("batch")
+	toBatch, _ := cmd.Flags().GetInt("to-batch")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	execParams := RunExecParams{
 		Command: "migrate:rollback",
 		Flags: map[string]any{
-			"all":     allFlag,
-			"dry-run": dryRun,
+			"all":      allFlag,
+			"step":     step,
+			"batch":    batch,
+			"to-batch": toBatch,
+			"dry-run":  dryRun,
+			"output":   outputFlag(cmd),
 		},
 	}
 	if err := runMigrations(execParams); err != nil {
-		fmt.Println(term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateSQLCmd = &cobra.Command{
+	Use:   "migrate:sql",
+	Short: "Writes migrations as a standalone SQL script",
+	Long: `Renders the selected migrations into one SQL script for the configured dialect,
+without connecting to the database. Each migration runs in its own transaction together
+with the statement that records it in the migrations table, so the script can be
+reviewed and run by hand.`,
+	Run: migrateSQL,
+}
+
+func init() {
+	migrateSQLCmd.Flags().StringP("out", "o", "", "Write the script to this file (default: stdout)")
+	migrateSQLCmd.Flags().String("from", "", "Last migration already applied; the script starts after it")
+	migrateSQLCmd.Flags().String("to", "", "Last migration to include (default: the latest)")
+	migrateSQLCmd.Flags().Bool("down", false, "Write Down migrations, most recent first")
+}
+
+func migrateSQL(cmd *cobra.Command, args []string) {
+	out, _ := cmd.Flags().GetString("out")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	down, _ := cmd.Flags().GetBool("down")
+
+	if out != "" {
+		abs, err := filepath.Abs(out)
+		if err != nil {
+			fmt.Println(term.RedText(fmt.Sprintf("Invalid --out path: %v", err)))
+			os.Exit(1)
+		}
+		out = abs
+	}
+
+	execParams := RunExecParams{
+		Command: "migrate:sql",
+		Flags: map[string]any{
+			"out":  out,
+			"from": from,
+			"to":   to,
+			"down": down,
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Println(term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateToCmd = &cobra.Command{
+	Use:   "migrate:to <migration_name>",
+	Short: "Migrates or rolls back the database to a specific migration",
+	Long: `Applies every pending migration up to and including the named one, and rolls back
+every applied migration after it. With --dry-run, prints the plan and its SQL.`,
+	Run: migrateToJone,
+}
+
+func init() {
+	migrateToCmd.Flags().Bool("dry-run", false, "Show the plan and SQL without executing")
+	addOutputFlag(migrateToCmd)
+}
+
+func migrateToJone(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Please provide the target migration name")
+		return
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	execParams := RunExecParams{
+		Command: "migrate:to",
+		Args:    args[:1],
+		Flags: map[string]any{
+			"dry-run": dryRun,
+			"output":  outputFlag(cmd),
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migrations: %v", err)))
+		os.Exit(1)
+	}
+}
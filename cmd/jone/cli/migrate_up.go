@@ -17,6 +17,7 @@ var migrateUpCmd = &cobra.Command{
 
 func init() {
 	migrateUpCmd.Flags().Bool("dry-run", false, "Show SQL without executing")
+	addOutputFlag(migrateUpCmd)
 }
 
 func migrateUpJone(cmd *cobra.Command, args []string) {
@@ -26,10 +27,11 @@ func migrateUpJone(cmd *cobra.Command, args []string) {
 		Args:    args,
 		Flags: map[string]any{
 			"dry-run": dryRun,
+			"output":  outputFlag(cmd),
 		},
 	}
 	if err := runMigrations(execParams); err != nil {
-		fmt.Println(term.RedText(fmt.Sprintf("Error running migration: %v", err)))
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Error running migration: %v", err)))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var migrateValidateCmd = &cobra.Command{
+	Use:   "migrate:validate",
+	Short: "Checks that applied migrations have not been edited",
+	Long: `Compares the checksum stored for each applied migration with the SQL it generates now.
+Use --repair to store the current checksums after an intentional change.`,
+	Run: migrateValidate,
+}
+
+func init() {
+	migrateValidateCmd.Flags().Bool("repair", false, "Store current checksums for changed migrations")
+}
+
+func migrateValidate(cmd *cobra.Command, args []string) {
+	repair, _ := cmd.Flags().GetBool("repair")
+	execParams := RunExecParams{
+		Command: "migrate:validate",
+		Flags: map[string]any{
+			"repair": repair,
+		},
+	}
+	if err := runMigrations(execParams); err != nil {
+		fmt.Println(term.RedText(fmt.Sprintf("Error validating migrations: %v", err)))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/spf13/cobra"
+)
+
+// addOutputFlag adds --output to a command that can print JSON.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
+
+// outputFlag returns the --output format, exiting if it isn't text or json.
+func outputFlag(cmd *cobra.Command) string {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" {
+		fmt.Fprintln(os.Stderr, term.RedText(fmt.Sprintf("Unknown output format %q: use text or json", output)))
+		os.Exit(1)
+	}
+	return output
+}
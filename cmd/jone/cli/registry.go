@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -33,11 +37,32 @@ func RegenerateRegistry(projectRoot string) error {
 			continue
 		}
 		if MigrationDirPattern.MatchString(name) {
-			migrations = append(migrations, templates.MigrationInfo{
+			dir := filepath.Join(migrationsRoot, name)
+			info := templates.MigrationInfo{
 				Name:       name,
 				Alias:      aliasFromFolder(name),
 				ImportPath: modulePath + "/" + MigrationsPath + "/" + name,
-			})
+			}
+			sqlFiles, err := inspectSQLMigration(dir)
+			if err != nil {
+				return fmt.Errorf("reading migration %s: %w", name, err)
+			}
+			if sqlFiles.up {
+				if err := writeSQLEmbed(dir); err != nil {
+					return fmt.Errorf("embedding migration %s: %w", name, err)
+				}
+				info.SQL, info.HasDownSQL = true, sqlFiles.down
+				info.DisableTransaction = sqlFiles.noTransaction
+			} else {
+				decls, err := inspectMigration(dir)
+				if err != nil {
+					return fmt.Errorf("reading migration %s: %w", name, err)
+				}
+				info.UpReturnsError = decls.upReturnsError
+				info.DownReturnsError = decls.downReturnsError
+				info.HasOptions = decls.hasOptions
+			}
+			migrations = append(migrations, info)
 		}
 	}
 
@@ -77,6 +102,138 @@ func writeRegistryFile(migrationsRoot string, migrations []templates.MigrationIn
 	return nil
 }
 
+// migrationDecls describes the top-level declarations of a migration package
+// that the registry needs to know about.
+type migrationDecls struct {
+	upReturnsError   bool // Up has the func(*jone.Schema) error form
+	downReturnsError bool // Down has the func(*jone.Schema) error form
+	hasOptions       bool // The package declares a package-level Options variable
+}
+
+// inspectMigration parses the migration folder's Go files for the signatures of
+// Up and Down and for a package-level Options variable.
+func inspectMigration(dir string) (migrationDecls, error) {
+	var decls migrationDecls
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return decls, err
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return decls, err
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil || !returnsError(decl.Type) {
+					continue
+				}
+				switch decl.Name.Name {
+				case "Up":
+					decls.upReturnsError = true
+				case "Down":
+					decls.downReturnsError = true
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					for _, ident := range spec.(*ast.ValueSpec).Names {
+						if ident.Name == "Options" {
+							decls.hasOptions = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls, nil
+}
+
+// sqlMigrationFiles records which SQL files a migration folder has.
+type sqlMigrationFiles struct {
+	up            bool // up.sql exists, so this is a SQL migration
+	down          bool // down.sql exists
+	noTransaction bool // up.sql starts with NoTransactionMarker among its leading comments
+}
+
+// inspectSQLMigration checks the migration folder for up.sql and down.sql. A
+// folder with up.sql can't also hold Go code, apart from the generated embed.go.
+func inspectSQLMigration(dir string) (sqlMigrationFiles, error) {
+	var files sqlMigrationFiles
+	up, err := os.ReadFile(filepath.Join(dir, UpSQLFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return files, err
+	}
+	files.up = true
+	files.noTransaction = hasNoTransactionMarker(string(up))
+
+	if _, err := os.Stat(filepath.Join(dir, DownSQLFile)); err == nil {
+		files.down = true
+	} else if !os.IsNotExist(err) {
+		return files, err
+	}
+
+	goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return files, err
+	}
+	for _, path := range goFiles {
+		if filepath.Base(path) != SQLEmbedFile {
+			return files, fmt.Errorf("has both %s and %s; a migration is either SQL files or Go code", UpSQLFile, filepath.Base(path))
+		}
+	}
+	return files, nil
+}
+
+// hasNoTransactionMarker reports whether NoTransactionMarker is one of the
+// comment lines that start script, before its first statement.
+func hasNoTransactionMarker(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == NoTransactionMarker:
+			return true
+		case line != "" && !strings.HasPrefix(line, "--"):
+			return false
+		}
+	}
+	return false
+}
+
+// writeSQLEmbed writes the generated embed.go of a SQL migration, if it changed.
+func writeSQLEmbed(dir string) error {
+	content, err := templates.RenderSQLEmbed()
+	if err != nil {
+		return fmt.Errorf("rendering embed template: %w", err)
+	}
+	dest := filepath.Join(dir, SQLEmbedFile)
+	if existing, err := os.ReadFile(dest); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	return os.WriteFile(dest, content, 0o644)
+}
+
+// returnsError reports whether fn has a single error result.
+func returnsError(fn *ast.FuncType) bool {
+	results := fn.Results
+	if results == nil || len(results.List) != 1 {
+		return false
+	}
+	ident, ok := results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
 // aliasFromFolder extracts a valid Go identifier from a migration folder name.
 // e.g., "20260114035749_add_users" -> "m20260114035749"
 func aliasFromFolder(folder string) string {
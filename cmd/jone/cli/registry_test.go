@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files under root, keyed by their slash-separated path.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHasNoTransactionMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"first line", "-- jone:no-transaction\nCREATE INDEX CONCURRENTLY i ON t (a);\n", true},
+		{"among leading comments", "\n-- Build the index online\n  -- jone:no-transaction\nCREATE INDEX CONCURRENTLY i ON t (a);\n", true},
+		{"after a statement", "SELECT 1;\n-- jone:no-transaction\n", false},
+		{"absent", "-- up.sql\nCREATE TABLE t (a int);\n", false},
+		{"different marker", "-- jone:no-transactions\nSELECT 1;\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasNoTransactionMarker(tt.script); got != tt.want {
+				t.Errorf("hasNoTransactionMarker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegenerateRegistry_SQLNoTransaction(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+		MigrationsPath + "/20260101000000_users/" + UpSQLFile:   "CREATE TABLE users (id int);\n",
+		MigrationsPath + "/20260101000000_users/" + DownSQLFile: "DROP TABLE users;\n",
+		MigrationsPath + "/20260102000000_index/" + UpSQLFile:   NoTransactionMarker + "\nCREATE INDEX CONCURRENTLY users_id ON users (id);\n",
+	})
+
+	if err := RegenerateRegistry(root); err != nil {
+		t.Fatalf("RegenerateRegistry() error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(root, MigrationsPath, "registry", "registry.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := strings.Join(strings.Fields(string(content)), " ") // Ignore gofmt alignment
+	_, index, _ := strings.Cut(registry, `Name: "20260102000000_index"`)
+	if !strings.Contains(index, "Options: jone.MigrationOptions{DisableTransaction: true}") {
+		t.Errorf("registry.go doesn't disable the transaction of the marked migration:\n%s", content)
+	}
+	if strings.Count(registry, "Options:") != 1 {
+		t.Errorf("registry.go sets Options on an unmarked migration:\n%s", content)
+	}
+}
@@ -20,12 +20,24 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Rebuild the migration runner instead of reusing the cached one")
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(migrateMakeCmd)
 	rootCmd.AddCommand(migrateLatestCmd)
 	rootCmd.AddCommand(migrateUpCmd)
 	rootCmd.AddCommand(migrateDownCmd)
 	rootCmd.AddCommand(migrateRollbackCmd)
+	rootCmd.AddCommand(migrateToCmd)
+	rootCmd.AddCommand(migrateResetCmd)
+	rootCmd.AddCommand(migrateRefreshCmd)
+	rootCmd.AddCommand(migrateFreshCmd)
+	rootCmd.AddCommand(migrateBaselineCmd)
+	rootCmd.AddCommand(migrateFakeCmd)
+	rootCmd.AddCommand(migrateSQLCmd)
 	rootCmd.AddCommand(migrateListCmd)
+	rootCmd.AddCommand(migrateValidateCmd)
+	rootCmd.AddCommand(migrateHistoryCmd)
+	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(versionCmd)
 }
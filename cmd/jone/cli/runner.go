@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/Grandbusta/jone/cmd/jone/templates"
 	"github.com/Grandbusta/jone/internal/term"
@@ -33,30 +35,24 @@ func runMigrations(params RunExecParams) error {
 	if modulePath == "" {
 		return fmt.Errorf("could not read module path. Ensure go.mod exists and contains a valid module declaration")
 	}
-	// Generate runner in .runner folder
-	runnerDir := filepath.Join(cwd, JoneFolderPath, ".runner")
-	if err := os.MkdirAll(runnerDir, 0o755); err != nil {
-		return fmt.Errorf("creating .runner directory: %w", err)
-	}
 
-	// Cleanup .runner directory after execution
-	defer func() {
-		if err := os.RemoveAll(runnerDir); err != nil {
-			fmt.Printf("Warning: failed to cleanup .runner directory: %v\n", err)
-		}
-	}()
-
-	runnerPath := filepath.Join(runnerDir, "main.go")
-	binaryPath := filepath.Join(runnerDir, "runner")
+	// Pick up migrations edited since the registry was generated, such as a
+	// changed Up signature or a new Options variable
+	if err := RegenerateRegistry(cwd); err != nil {
+		return fmt.Errorf("regenerating registry: %w", err)
+	}
 
-	if err := generateRunner(runnerPath, modulePath); err != nil {
+	runner, err := renderRunner(modulePath)
+	if err != nil {
 		return fmt.Errorf("generating runner: %w", err)
 	}
 
-	// Build runner
-	if err := buildRunner(cwd, runnerPath, binaryPath); err != nil {
-		return fmt.Errorf("building runner: %w", err)
+	// Reuse the runner built from the same sources, or build it
+	binaryPath, cleanup, err := runnerBinary(cwd, runner)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
 	// Execute runner
 	if err := executeRunner(binaryPath, params); err != nil {
@@ -66,7 +62,8 @@ func runMigrations(params RunExecParams) error {
 	return nil
 }
 
-func generateRunner(runnerPath, modulePath string) error {
+// renderRunner returns the source of the runner's main.go.
+func renderRunner(modulePath string) ([]byte, error) {
 	registryPackage := modulePath + "/" + MigrationsPath + "/registry"
 	configPackage := modulePath + "/" + JoneFolderPath
 
@@ -76,20 +73,62 @@ func generateRunner(runnerPath, modulePath string) error {
 		ConfigPackage:   configPackage,
 	})
 	if err != nil {
-		return fmt.Errorf("rendering runner template: %w", err)
+		return nil, fmt.Errorf("rendering runner template: %w", err)
+	}
+	return content, nil
+}
+
+// runnerBinary returns the path of a runner binary built from runner. The
+// cached binary is reused unless --no-cache is set; cleanup removes the
+// binary when it isn't cached.
+func runnerBinary(cwd string, runner []byte) (binaryPath string, cleanup func(), err error) {
+	if !noCache {
+		cacheDir, err := runnerCacheDir(cwd)
+		if err == nil {
+			binaryPath, err := cachedRunner(cwd, cacheDir, runner)
+			return binaryPath, func() {}, err
+		}
+		fmt.Fprintln(os.Stderr, term.YellowText(fmt.Sprintf("Warning: runner cache unavailable, building without it: %v", err)))
+	}
+
+	runnerDir := filepath.Join(cwd, JoneFolderPath, ".runner")
+	binaryPath = filepath.Join(runnerDir, "runner")
+	cleanup = func() {
+		if err := os.RemoveAll(runnerDir); err != nil {
+			fmt.Printf("Warning: failed to cleanup .runner directory: %v\n", err)
+		}
+	}
+	if err := compileRunner(cwd, runner, binaryPath); err != nil {
+		cleanup()
+		return "", nil, err
 	}
+	return binaryPath, cleanup, nil
+}
 
-	if err := os.WriteFile(runnerPath, content, 0o644); err != nil {
-		return fmt.Errorf("writing runner file: %w", err)
+// compileRunner writes runner to jone/.runner/main.go, inside the module so
+// its imports resolve, builds it to binaryPath and removes the source.
+func compileRunner(cwd string, runner []byte, binaryPath string) error {
+	runnerDir := filepath.Join(cwd, JoneFolderPath, ".runner")
+	if err := os.MkdirAll(runnerDir, 0o755); err != nil {
+		return fmt.Errorf("creating .runner directory: %w", err)
 	}
+	runnerPath := filepath.Join(runnerDir, "main.go")
+	defer os.Remove(runnerPath)
 
+	if err := os.WriteFile(runnerPath, runner, 0o644); err != nil {
+		return fmt.Errorf("generating runner: writing runner file: %w", err)
+	}
+	if err := buildRunner(cwd, runnerPath, binaryPath); err != nil {
+		return fmt.Errorf("building runner: %w", err)
+	}
 	return nil
 }
 
 func buildRunner(cwd, runnerPath, binaryPath string) error {
 	buildCmd := exec.Command("go", "build", "-o", binaryPath, runnerPath)
 	buildCmd.Dir = cwd
-	buildCmd.Stdout = os.Stdout
+	// Nothing the build prints belongs on stdout, which may carry JSON events
+	buildCmd.Stdout = os.Stderr
 	buildCmd.Stderr = os.Stderr
 
 	if err := buildCmd.Run(); err != nil {
@@ -100,7 +139,8 @@ func buildRunner(cwd, runnerPath, binaryPath string) error {
 }
 
 func executeRunner(binaryPath string, params RunExecParams) error {
-	fmt.Println(term.CyanText(fmt.Sprintf("Running migrations (%s)...", params.Command)))
+	// Status goes to stderr so commands like migrate:sql can write to stdout
+	fmt.Fprintln(os.Stderr, term.CyanText(fmt.Sprintf("Running migrations (%s)...", params.Command)))
 
 	// Build command arguments: command + flags + args
 	cmdArgs := []string{params.Command}
@@ -130,7 +170,34 @@ func executeRunner(binaryPath string, params RunExecParams) error {
 	runCmd.Stdout = os.Stdout
 	runCmd.Stderr = os.Stderr
 
-	if err := runCmd.Run(); err != nil {
+	// Let the runner handle interrupts: it cancels the running migration and
+	// rolls back. Ctrl-C already reaches it through the process group; SIGTERM
+	// sent to jone alone is forwarded. Either way jone waits for the runner to
+	// exit so the .runner directory still gets cleaned up.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	if err := runCmd.Start(); err != nil {
+		return fmt.Errorf("starting runner: %w", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if sig != os.Interrupt {
+					_ = runCmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := runCmd.Wait(); err != nil {
 		return fmt.Errorf("runner execution failed: %w", err)
 	}
 
@@ -3,12 +3,12 @@ package cli
 import (
 	"fmt"
 
+	"github.com/Grandbusta/jone/internal/version"
 	"github.com/spf13/cobra"
 )
 
-// Version is the current version of jone.
-// Update this before each release.
-var Version = "v0.2.0"
+// Version is the current version of jone, set in internal/version.
+var Version = version.Version
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
@@ -19,6 +19,9 @@ import (
 {{- else if eq .Database "sqlite" }}
 	// Database driver
 	_ "github.com/mattn/go-sqlite3"
+{{- else if eq .Database "mssql" }}
+	// Database driver
+	_ "github.com/microsoft/go-mssqldb"
 {{- else }}
 	// Database driver
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -40,6 +43,15 @@ var Config = jone.Config{
 	Connection: jone.Connection{
 		Database: "./jone.db",
 	},
+{{- else if eq .Database "mssql" }}
+	Client:     "mssql",
+	Connection: jone.Connection{
+		Host:     "localhost",
+		Port:     "1433",
+		User:     "sa",
+		Password: "password",
+		Database: "my_db",
+	},
 {{- else }}
 	Client:     "postgresql",
 	Connection: jone.Connection{
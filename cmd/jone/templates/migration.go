@@ -2,11 +2,19 @@ package templates
 
 import "text/template"
 
-// MigrationStubData holds data for the migration stub template.
+// MigrationStubData holds data for the migration stub templates.
 type MigrationStubData struct {
 	RuntimePackage string
+	Table          string // Table named by --create or --table
 }
 
+// Stub names. Each is also the file name that overrides it in jone/stubs/.
+const (
+	StubBlank  = "migration.stub"        // Empty Up and Down
+	StubCreate = "migration.create.stub" // migrate:make --create
+	StubTable  = "migration.table.stub"  // migrate:make --table
+)
+
 const migrationTemplateContent = `package migration
 
 import (
@@ -22,10 +30,94 @@ func Down(s *jone.Schema) {
 }
 `
 
+const migrationCreateTemplateContent = `package migration
+
+import (
+	"{{ .RuntimePackage }}"
+)
+
+func Up(s *jone.Schema) {
+	s.CreateTable({{ printf "%q" .Table }}, func(t *jone.Table) {
+		t.Increments("id")
+		t.Timestamps()
+	})
+}
+
+func Down(s *jone.Schema) {
+	s.DropTable({{ printf "%q" .Table }})
+}
+`
+
+const migrationTableTemplateContent = `package migration
+
+import (
+	"{{ .RuntimePackage }}"
+)
+
+func Up(s *jone.Schema) {
+	s.Table({{ printf "%q" .Table }}, func(t *jone.Table) {
+
+	})
+}
+
+func Down(s *jone.Schema) {
+	s.Table({{ printf "%q" .Table }}, func(t *jone.Table) {
+
+	})
+}
+`
+
 // Migration is the parsed template for generating migration stub files.
-var Migration = template.Must(template.New("migration").Parse(migrationTemplateContent))
+var Migration = template.Must(template.New(StubBlank).Parse(migrationTemplateContent))
+
+// Stubs holds the built-in migration stubs by name.
+var Stubs = map[string]*template.Template{
+	StubBlank:  Migration,
+	StubCreate: template.Must(template.New(StubCreate).Parse(migrationCreateTemplateContent)),
+	StubTable:  template.Must(template.New(StubTable).Parse(migrationTableTemplateContent)),
+}
 
 // RenderMigration generates the migration.go stub content.
 func RenderMigration(data MigrationStubData) ([]byte, error) {
 	return Render(Migration, data)
 }
+
+const sqlEmbedTemplateContent = `// Code generated by jone. DO NOT EDIT.
+
+package migration
+
+import "embed"
+
+// SQL holds this migration's up.sql and down.sql.
+//
+//go:embed *.sql
+var SQL embed.FS
+`
+
+// SQLEmbed is the parsed template for the file that embeds a SQL migration.
+var SQLEmbed = template.Must(template.New("sqlEmbed").Parse(sqlEmbedTemplateContent))
+
+// RenderSQLEmbed generates the embed.go file of a SQL migration.
+func RenderSQLEmbed() ([]byte, error) {
+	return Render(SQLEmbed, nil)
+}
+
+const sqlStubContent = `-- {{ .Direction }} migration: {{ .Name }}
+-- Statements end with ";". PostgreSQL function bodies can be quoted with $$,
+-- MySQL bodies can use DELIMITER and SQL Server batches can end with GO.
+
+`
+
+// SQLStubData holds data for the up.sql and down.sql stubs.
+type SQLStubData struct {
+	Name      string
+	Direction string // "Up" or "Down"
+}
+
+// SQLStub is the parsed template for up.sql and down.sql stubs.
+var SQLStub = template.Must(template.New("sqlStub").Parse(sqlStubContent))
+
+// RenderSQLStub generates an up.sql or down.sql stub.
+func RenderSQLStub(data SQLStubData) ([]byte, error) {
+	return Render(SQLStub, data)
+}
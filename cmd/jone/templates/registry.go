@@ -10,9 +10,15 @@ type RegistryData struct {
 
 // MigrationInfo holds data for a single migration in the registry template.
 type MigrationInfo struct {
-	Name       string // Folder name (e.g., "20260114035749_add_users")
-	Alias      string // Import alias (e.g., "m20260114035749")
-	ImportPath string // Full import path
+	Name               string // Folder name (e.g., "20260114035749_add_users")
+	Alias              string // Import alias (e.g., "m20260114035749")
+	ImportPath         string // Full import path
+	UpReturnsError     bool   // Up has the func(*jone.Schema) error signature
+	DownReturnsError   bool   // Down has the func(*jone.Schema) error signature
+	HasOptions         bool   // The migration declares var Options jone.MigrationOptions
+	SQL                bool   // The migration is up.sql and down.sql, embedded as SQL
+	HasDownSQL         bool   // The SQL migration has a down.sql
+	DisableTransaction bool   // The SQL migration's up.sql has the no-transaction marker
 }
 
 const registryTemplateContent = `// Code generated by jone. DO NOT EDIT.
@@ -29,8 +35,20 @@ var Registrations = []jone.Registration{
 {{- range .Migrations }}
 	{
 		Name: "{{ .Name }}",
-		Up:   {{ .Alias }}.Up,
-		Down: {{ .Alias }}.Down,
+		{{- if .SQL }}
+		UpE: jone.SQLFile({{ .Alias }}.SQL, "up.sql"),
+		{{- if .HasDownSQL }}
+		DownE: jone.SQLFile({{ .Alias }}.SQL, "down.sql"),
+		{{- end }}
+		{{- else }}
+		{{ if .UpReturnsError }}UpE{{ else }}Up{{ end }}: {{ .Alias }}.Up,
+		{{ if .DownReturnsError }}DownE{{ else }}Down{{ end }}: {{ .Alias }}.Down,
+		{{- end }}
+		{{- if .HasOptions }}
+		Options: {{ .Alias }}.Options,
+		{{- else if .DisableTransaction }}
+		Options: jone.MigrationOptions{DisableTransaction: true},
+		{{- end }}
 	},
 {{- end }}
 }
@@ -15,37 +15,128 @@ const runnerTemplateContent = `
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"{{ .RuntimePackage }}"
 	"{{ .RegistryPackage }}"
 	joneconfig "{{ .ConfigPackage }}"
 )
 
+const usage = ` + "`" + `Usage: migrate <command> [flags] [args]
+
+Commands:
+  latest              Run all pending migrations
+  up [name]           Run the next pending migration, or the named one
+  down [name]         Roll back the last migration, or the named one
+  rollback            Roll back the last batch
+  to <name>           Migrate or roll back to the named migration
+  reset               Roll back every migration
+  refresh             Roll back every migration and run them all again
+  fresh               Drop every object and run all migrations
+  list, status        List migrations and their status
+  history             Show every migration run
+  validate            Check applied migrations haven't been edited
+  baseline <name>     Mark migrations up to the named one as applied
+  fake <name>         Mark one migration as applied
+  sql                 Write migrations as a SQL script
+
+Commands can also be given as migrate:<command>. Run migrate -h to list the
+flags. JONE_CLIENT, JONE_DB_HOST, JONE_DB_PORT, JONE_DB_USER,
+JONE_DB_PASSWORD, JONE_DB_NAME, JONE_DB_SSLMODE and JONE_ENV override the
+config.
+
+Exit status is 0 on success, 1 if the command failed and 2 for usage errors.
+` + "`" + `
+
+// commands maps each command to its function and the start of its error message.
+var commands = map[string]struct {
+	run     func(jone.RunParams) error
+	failure string
+}{
+	"migrate:latest":   {jone.RunLatest, "Migration failed"},
+	"migrate:up":       {jone.RunUp, "Migration failed"},
+	"migrate:down":     {jone.RunDown, "Rollback failed"},
+	"migrate:rollback": {jone.RunRollback, "Rollback failed"},
+	"migrate:to":       {jone.RunTo, "Migration failed"},
+	"migrate:reset":    {jone.RunReset, "Reset failed"},
+	"migrate:refresh":  {jone.RunRefresh, "Refresh failed"},
+	"migrate:fresh":    {jone.RunFresh, "Fresh failed"},
+	"migrate:list":     {jone.RunList, "List failed"},
+	"migrate:history":  {jone.RunHistory, "History failed"},
+	"migrate:validate": {jone.RunValidate, "Validation failed"},
+	"migrate:baseline": {jone.RunBaseline, "Baseline failed"},
+	"migrate:fake":     {jone.RunFake, "Fake failed"},
+	"migrate:sql":      {jone.RunSQL, "Writing SQL failed"},
+}
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: runner <migrate:latest|migrate:down|migrate:rollback> [flags]")
-		os.Exit(1)
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
 
 	command := os.Args[1]
+	switch command {
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	case "status", "migrate:status":
+		command = "migrate:list"
+	}
+	if !strings.HasPrefix(command, "migrate:") {
+		command = "migrate:" + command
+	}
+	cmd, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
 
 	// Define flags
 	allFlag := flag.Bool("all", false, "Rollback all migrations")
+	stepFlag := flag.Int("step", 0, "Rollback the last N migrations")
+	batchFlag := flag.Int("batch", 0, "Rollback batch N")
+	toBatchFlag := flag.Int("to-batch", 0, "Rollback every batch above N")
 	dryRunFlag := flag.Bool("dry-run", false, "Show SQL without executing")
+	repairFlag := flag.Bool("repair", false, "Store current checksums")
+	allowMissingFlag := flag.Bool("allow-missing", false, "Run even if applied migrations are missing from the registry")
+	forceFlag := flag.Bool("force", false, "Run destructive commands outside a non-production environment")
+	singleTxFlag := flag.Bool("single-transaction", false, "Run all pending migrations in one transaction")
+	fromFlag := flag.String("from", "", "Start the script after this migration")
+	toFlag := flag.String("to", "", "End the script with this migration")
+	downFlag := flag.Bool("down", false, "Write Down migrations, or remove the faked record")
+	outFlag := flag.String("out", "", "Write the script to this file")
+	outputFlag := flag.String("output", "", "Output format: text or json")
+	checkFlag := flag.Bool("check", false, "Fail if any migration is pending")
 
 	// Parse flags (skip command name)
 	flag.CommandLine.Parse(os.Args[2:])
 
 	cfg := &joneconfig.Config
+	cfg.ApplyEnv()
+
+	// Cancel on Ctrl-C or SIGTERM so the running migration's transaction is rolled back
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Create schema and open database connection
-	s := jone.NewSchema(cfg)
-	if !*dryRunFlag {
+	s, err := jone.NewSchema(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	s = s.WithContext(ctx)
+	// Dry runs connect too, to query the tracking table.
+	// migrate:sql only renders a script, so it never connects.
+	if command != "migrate:sql" {
 		if err := s.Open(); err != nil {
-			fmt.Printf("Failed to connect to database: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
 			os.Exit(1)
 		}
 		defer s.Close()
@@ -55,41 +146,29 @@ func main() {
 		Config:        cfg,
 		Registrations: registry.Registrations,
 		Schema:        s,
+		Context:       ctx,
 		Options: jone.RunOptions{
-			All:    *allFlag,
-			DryRun: *dryRunFlag,
-			Args:   flag.Args(),
+			All:               *allFlag,
+			Step:              *stepFlag,
+			Batch:             *batchFlag,
+			ToBatch:           *toBatchFlag,
+			DryRun:            *dryRunFlag,
+			Repair:            *repairFlag,
+			AllowMissing:      *allowMissingFlag,
+			Force:             *forceFlag,
+			SingleTransaction: *singleTxFlag,
+			From:              *fromFlag,
+			To:                *toFlag,
+			Down:              *downFlag,
+			Out:               *outFlag,
+			Output:            *outputFlag,
+			Check:             *checkFlag,
+			Args:              flag.Args(),
 		},
 	}
 
-	switch command {
-	case "migrate:latest":
-		if err := jone.RunLatest(params); err != nil {
-			fmt.Printf("Migration failed: %v\n", err)
-			os.Exit(1)
-		}
-	case "migrate:list":
-		if err := jone.RunList(params); err != nil {
-			fmt.Printf("List failed: %v\n", err)
-			os.Exit(1)
-		}
-	case "migrate:up":
-		if err := jone.RunUp(params); err != nil {
-			fmt.Printf("Migration failed: %v\n", err)
-			os.Exit(1)
-		}
-	case "migrate:down":
-		if err := jone.RunDown(params); err != nil {
-			fmt.Printf("Rollback failed: %v\n", err)
-			os.Exit(1)
-		}
-	case "migrate:rollback":
-		if err := jone.RunRollback(params); err != nil {
-			fmt.Printf("Rollback failed: %v\n", err)
-			os.Exit(1)
-		}
-	default:
-		fmt.Printf("Unknown command: %s\n", command)
+	if err := cmd.run(params); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.failure, err)
 		os.Exit(1)
 	}
 }
@@ -2,6 +2,8 @@
 package config
 
 import (
+	"os"
+	"strings"
 	"time"
 )
 
@@ -11,6 +13,56 @@ type Config struct {
 	Connection Connection
 	Pool       Pool
 	Migrations Migrations
+	// Environment names the deployment this config targets (e.g. "development").
+	// Destructive commands such as migrate:fresh only run when it is set to a
+	// non-production value.
+	Environment string
+}
+
+// IsProduction reports whether destructive commands should be refused: the
+// Environment is unset or names a production deployment.
+func (c *Config) IsProduction() bool {
+	switch strings.ToLower(strings.TrimSpace(c.Environment)) {
+	case "", "production", "prod":
+		return true
+	}
+	return false
+}
+
+// Environment variables that override the config at run time, so one built
+// migration binary can be pointed at any database.
+const (
+	EnvClient      = "JONE_CLIENT"
+	EnvHost        = "JONE_DB_HOST"
+	EnvPort        = "JONE_DB_PORT"
+	EnvUser        = "JONE_DB_USER"
+	EnvPassword    = "JONE_DB_PASSWORD"
+	EnvDatabase    = "JONE_DB_NAME"
+	EnvSSLMode     = "JONE_DB_SSLMODE"
+	EnvEnvironment = "JONE_ENV"
+)
+
+// ApplyEnv replaces settings with the values of the Env* variables that are
+// set. Unset variables leave the config unchanged.
+func (c *Config) ApplyEnv() {
+	c.applyEnv(os.LookupEnv)
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) {
+	for env, field := range map[string]*string{
+		EnvClient:      &c.Client,
+		EnvHost:        &c.Connection.Host,
+		EnvPort:        &c.Connection.Port,
+		EnvUser:        &c.Connection.User,
+		EnvPassword:    &c.Connection.Password,
+		EnvDatabase:    &c.Connection.Database,
+		EnvSSLMode:     &c.Connection.SSLMode,
+		EnvEnvironment: &c.Environment,
+	} {
+		if value, ok := lookup(env); ok {
+			*field = value
+		}
+	}
 }
 
 // Pool holds connection pool configuration.
@@ -35,4 +87,24 @@ type Connection struct {
 // Migrations holds migration-specific configuration.
 type Migrations struct {
 	TableName string
+	// LockTimeout is how long a run waits for another process to release the
+	// migration lock before giving up. 0 means DefaultLockTimeout.
+	LockTimeout time.Duration
+	// OutOfOrder decides what latest does with pending migrations older than
+	// the newest applied one (e.g. after merging branches): OutOfOrderError,
+	// OutOfOrderWarn or OutOfOrderAllow. Empty means OutOfOrderWarn.
+	OutOfOrder string
+	// SingleTransaction runs all pending migrations of a latest run in one
+	// transaction, as if --single-transaction were always passed.
+	SingleTransaction bool
 }
+
+// Out-of-order migration policies for Migrations.OutOfOrder.
+const (
+	OutOfOrderError = "error" // Refuse to run
+	OutOfOrderWarn  = "warn"  // Print a warning and run them
+	OutOfOrderAllow = "allow" // Run them silently
+)
+
+// DefaultLockTimeout is the migration lock wait used when Migrations.LockTimeout is 0.
+const DefaultLockTimeout = time.Minute
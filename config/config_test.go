@@ -78,3 +78,47 @@ func TestConfig_PoolFieldExists(t *testing.T) {
 		t.Errorf("Config.Pool.ConnMaxIdleTime = %v, want %v", cfg.Pool.ConnMaxIdleTime, 5*time.Minute)
 	}
 }
+
+func TestConfig_IsProduction(t *testing.T) {
+	tests := []struct {
+		env  string
+		want bool
+	}{
+		{"", true},
+		{"production", true},
+		{"Prod", true},
+		{"development", false},
+		{"staging", false},
+		{"test", false},
+	}
+	for _, tt := range tests {
+		cfg := Config{Environment: tt.env}
+		if got := cfg.IsProduction(); got != tt.want {
+			t.Errorf("IsProduction() with Environment %q = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	cfg := Config{
+		Client:     "postgres",
+		Connection: Connection{Host: "localhost", Port: "5432", User: "dev", Database: "app_dev"},
+	}
+	env := map[string]string{
+		EnvHost:     "db.internal",
+		EnvPassword: "secret",
+		EnvSSLMode:  "",
+	}
+	cfg.applyEnv(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	})
+
+	want := Connection{Host: "db.internal", Port: "5432", User: "dev", Password: "secret", Database: "app_dev", SSLMode: ""}
+	if cfg.Connection != want {
+		t.Errorf("Connection = %+v, want %+v", cfg.Connection, want)
+	}
+	if cfg.Client != "postgres" {
+		t.Errorf("Client = %q, want it unchanged", cfg.Client)
+	}
+}
@@ -2,6 +2,9 @@
 package dialect
 
 import (
+	"database/sql"
+	"fmt"
+
 	"github.com/Grandbusta/jone/config"
 	"github.com/Grandbusta/jone/types"
 )
@@ -57,10 +60,6 @@ type Dialect interface {
 	// CreateMigrationsTableSQL returns SQL to create the migrations tracking table.
 	CreateMigrationsTableSQL(tableName string) string
 
-	// InsertMigrationSQL returns parameterized SQL to record a migration.
-	// Parameters: $1=name, $2=batch
-	InsertMigrationSQL(tableName string) string
-
 	// DeleteMigrationSQL returns parameterized SQL to remove a migration record.
 	// Parameters: $1=name
 	DeleteMigrationSQL(tableName string) string
@@ -76,15 +75,124 @@ type Dialect interface {
 	GetMigrationsByBatchSQL(tableName string) string
 }
 
-// GetDialect returns a dialect implementation by name.
-func GetDialect(name string) Dialect {
-	switch name {
-	case "postgresql", "postgres", "pg":
-		return &PostgresDialect{}
-	case "mysql":
-		return &MySQLDialect{}
-	default:
-		// Default to PostgreSQL
-		return &PostgresDialect{}
+// Queryer runs read-only queries (both *sql.DB and *sql.Tx satisfy it).
+type Queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// TableRenamer is implemented by dialects that can't rename a table with
+// ALTER TABLE ... RENAME TO, such as SQL Server, or that write it differently.
+// RenameTableSQL uses it when it is available.
+type TableRenamer interface {
+	// RenameTableSQL generates a statement that renames a table within its schema.
+	RenameTableSQL(schema, oldName, newName string) string
+}
+
+// RenameTableSQL returns the statement that renames a table within its schema:
+// d's own if it implements TableRenamer, or ALTER TABLE ... RENAME TO.
+func RenameTableSQL(d Dialect, schema, oldName, newName string) string {
+	if renamer, ok := d.(TableRenamer); ok {
+		return renamer.RenameTableSQL(schema, oldName, newName)
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+		d.QualifyTable(schema, oldName),
+		d.QuoteIdentifier(newName))
+}
+
+// Placeholders is implemented by dialects whose driver doesn't take "?" bind
+// parameters, such as PostgreSQL's $1.
+type Placeholders interface {
+	// Placeholder returns the bind parameter for argument n, counting from 1.
+	Placeholder(n int) string
+}
+
+// Placeholder returns d's bind parameter for argument n, counting from 1.
+// Dialects that don't implement Placeholders use "?".
+func Placeholder(d Dialect, n int) string {
+	if p, ok := d.(Placeholders); ok {
+		return p.Placeholder(n)
 	}
+	return "?"
+}
+
+// TableRebuilder is implemented by dialects whose ALTER TABLE cannot express
+// every TableAction (e.g. SQLite). For those actions the table is recreated:
+// a new table is created with the desired definition, the rows are copied,
+// the old table is dropped and the new one is renamed into place.
+type TableRebuilder interface {
+	// NeedsRebuild reports whether any of the actions requires a table rebuild.
+	NeedsRebuild(actions []*types.TableAction) bool
+
+	// IntrospectTable reads the current definition of a table, including
+	// its indexes and foreign keys.
+	IntrospectTable(q Queryer, schema, tableName string) (*types.Table, error)
+
+	// RebuildTableSQL generates the statements that recreate the current
+	// table with all of the actions applied.
+	RebuildTableSQL(current *types.Table, actions []*types.TableAction) []string
+}
+
+// Locker is implemented by dialects with session-level advisory locks. The
+// runner holds the migration lock on a single connection for a whole run so
+// two processes cannot apply migrations at the same time. Dialects without it
+// fall back to a lock table.
+type Locker interface {
+	// TryLockSQL returns a query that tries to take the named lock without
+	// waiting and returns 1 if it was acquired. Parameters: $1=lock name
+	TryLockSQL() string
+
+	// UnlockSQL returns a statement that releases the named lock.
+	// Parameters: $1=lock name
+	UnlockSQL() string
+}
+
+// Introspector is implemented by dialects that can list every object in a
+// schema. It backs migrate:fresh.
+type Introspector interface {
+	// DropAllObjectsSQL lists the tables, views and user-defined types in
+	// schema (empty = default schema) using q, and returns the statements
+	// that drop them, skipping tables named in keep. The statements must run
+	// in order on a single connection.
+	DropAllObjectsSQL(q Queryer, schema string, keep []string) ([]string, error)
+}
+
+// TransactionalDDL is implemented by dialects that report whether DDL
+// statements can be rolled back as part of a transaction. Dialects without it
+// are assumed not to support it. It backs migrate:latest --single-transaction.
+type TransactionalDDL interface {
+	SupportsTransactionalDDL() bool
+}
+
+// StatementSplitter is implemented by dialects that can split a SQL script
+// into statements to run one at a time. It backs SQL file migrations; scripts
+// for other dialects run as a single statement.
+type StatementSplitter interface {
+	// SplitStatements returns the statements in script, without their
+	// terminators, following the dialect's client syntax for quoted function
+	// bodies and delimiters.
+	SplitStatements(script string) []string
+}
+
+// Scripter is implemented by dialects that can write migrations as a
+// standalone SQL script. It backs migrate:sql.
+type Scripter interface {
+	// BeginTransactionSQL returns the statement that starts a transaction.
+	BeginTransactionSQL() string
+
+	// CommitTransactionSQL returns the statement that commits it.
+	CommitTransactionSQL() string
+
+	// InsertMigrationScriptSQL returns parameterized SQL that records a
+	// migration in the batch after the current last one plus an offset.
+	// Parameters: $1=name, $2=batch offset (1 starts a new batch, 0 joins it), $3=checksum
+	InsertMigrationScriptSQL(tableName string) string
+
+	// InlineArgs returns query with its placeholders replaced by args
+	// written as SQL literals.
+	InlineArgs(query string, args []any) (string, error)
+
+	// TerminateStatement returns stmt as it must appear in a script for the
+	// dialect's command-line client, such as with its terminator or followed
+	// by a batch separator.
+	TerminateStatement(stmt string) string
 }
@@ -0,0 +1,38 @@
+package dialect
+
+import (
+	"fmt"
+	"slices"
+)
+
+// queryNames runs a query returning rows of string columns and returns them.
+// Rows whose first column is in skip are left out.
+func queryNames(q Queryer, skip []string, query string, args ...any) ([][]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]string
+	for rows.Next() {
+		row := make([]string, len(cols))
+		dest := make([]any, len(cols))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning object name: %w", err)
+		}
+		if slices.Contains(skip, row[0]) {
+			continue
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
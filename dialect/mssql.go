@@ -0,0 +1,708 @@
+package dialect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/types"
+)
+
+// MSSQLDialect implements Dialect for Microsoft SQL Server.
+//
+// Defaults and primary keys are created as named constraints
+// (DF_<table>_<column>, PK_<table>) so that later migrations can drop them;
+// SQL Server would otherwise generate random names.
+type MSSQLDialect struct{}
+
+func init() {
+	Register("mssql", func() Dialect { return &MSSQLDialect{} })
+	RegisterAlias("sqlserver", "mssql")
+}
+
+// Name returns "mssql".
+func (d *MSSQLDialect) Name() string {
+	return "mssql"
+}
+
+// DriverName returns "sqlserver" for the microsoft/go-mssqldb driver.
+func (d *MSSQLDialect) DriverName() string {
+	return "sqlserver"
+}
+
+// FormatDSN builds a sqlserver:// URL from the given parameters.
+// SSLMode maps to the driver's encrypt setting: "disable" turns encryption off,
+// "require" encrypts without verifying the certificate, "verify-full" verifies it.
+func (d *MSSQLDialect) FormatDSN(conn config.Connection) string {
+	host := conn.Host
+	if conn.Port != "" {
+		host = net.JoinHostPort(conn.Host, conn.Port)
+	}
+
+	query := url.Values{}
+	query.Set("database", conn.Database)
+	switch conn.SSLMode {
+	case "disable":
+		query.Set("encrypt", "disable")
+	case "require":
+		query.Set("encrypt", "true")
+		query.Set("TrustServerCertificate", "true")
+	case "verify-full":
+		query.Set("encrypt", "true")
+	}
+
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(conn.User, conn.Password),
+		Host:     host,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// QuoteIdentifier quotes an identifier with square brackets for SQL Server.
+func (d *MSSQLDialect) QuoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// CreateTableSQL generates a CREATE TABLE statement for SQL Server.
+func (d *MSSQLDialect) CreateTableSQL(table *types.Table) string {
+	var columns []string
+	for _, col := range table.Columns {
+		columns = append(columns, d.columnDefinitionSQL(table.Name, col))
+	}
+
+	return fmt.Sprintf(
+		"CREATE TABLE %s (\n  %s\n);",
+		d.QualifyTable(table.Schema, table.Name),
+		strings.Join(columns, ",\n  "),
+	)
+}
+
+// CreateTableIfNotExistsSQL generates a CREATE TABLE guarded by an OBJECT_ID check,
+// since SQL Server has no CREATE TABLE IF NOT EXISTS.
+func (d *MSSQLDialect) CreateTableIfNotExistsSQL(table *types.Table) string {
+	return fmt.Sprintf("IF OBJECT_ID(%s, N'U') IS NULL\n%s",
+		d.unicodeString(d.QualifyTable(table.Schema, table.Name)),
+		d.CreateTableSQL(table))
+}
+
+// DropTableSQL generates a DROP TABLE statement.
+func (d *MSSQLDialect) DropTableSQL(schema, name string) string {
+	return fmt.Sprintf("DROP TABLE %s;", d.QualifyTable(schema, name))
+}
+
+// DropTableIfExistsSQL generates a DROP TABLE IF EXISTS statement (SQL Server 2016+).
+func (d *MSSQLDialect) DropTableIfExistsSQL(schema, name string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.QualifyTable(schema, name))
+}
+
+// RenameTableSQL generates an sp_rename call. The new name must not be
+// quoted, otherwise the brackets become part of the name.
+func (d *MSSQLDialect) RenameTableSQL(schema, oldName, newName string) string {
+	return fmt.Sprintf("EXEC sp_rename %s, %s;",
+		d.unicodeString(d.QualifyTable(schema, oldName)),
+		d.unicodeString(newName))
+}
+
+// ColumnDefinitionSQL generates the column definition SQL.
+// Without a table name, defaults and primary keys are left unnamed; CREATE TABLE
+// and ALTER TABLE use named constraints instead.
+func (d *MSSQLDialect) ColumnDefinitionSQL(col *types.Column) string {
+	return d.columnDefinitionSQL("", col)
+}
+
+// columnDefinitionSQL generates the column definition SQL, naming the default and
+// primary key constraints after tableName when it is set.
+func (d *MSSQLDialect) columnDefinitionSQL(tableName string, col *types.Column) string {
+	var parts []string
+
+	parts = append(parts, d.QuoteIdentifier(col.Name))
+	parts = append(parts, d.mapDataType(col))
+
+	if col.IsNotNull || col.IsPrimaryKey {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.HasDefault {
+		def := fmt.Sprintf("DEFAULT %s", d.formatDefault(col.DefaultValue))
+		if tableName != "" {
+			def = fmt.Sprintf("CONSTRAINT %s %s", d.QuoteIdentifier(d.defaultConstraintName(tableName, col.Name)), def)
+		}
+		parts = append(parts, def)
+	}
+	if col.IsPrimaryKey {
+		if tableName != "" {
+			parts = append(parts, fmt.Sprintf("CONSTRAINT %s PRIMARY KEY", d.QuoteIdentifier(d.primaryKeyName(tableName))))
+		} else {
+			parts = append(parts, "PRIMARY KEY")
+		}
+	}
+	if col.IsUnique && !col.IsPrimaryKey {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.RefTable != "" && col.RefColumn != "" {
+		refPart := fmt.Sprintf(
+			"REFERENCES %s(%s)",
+			d.QuoteIdentifier(col.RefTable),
+			d.QuoteIdentifier(col.RefColumn),
+		)
+		if col.RefOnDelete != "" {
+			refPart += " ON DELETE " + col.RefOnDelete
+		}
+		if col.RefOnUpdate != "" {
+			refPart += " ON UPDATE " + col.RefOnUpdate
+		}
+		parts = append(parts, refPart)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// defaultConstraintName returns the name jone gives a column's default constraint.
+func (d *MSSQLDialect) defaultConstraintName(tableName, columnName string) string {
+	return "DF_" + tableName + "_" + columnName
+}
+
+// primaryKeyName returns the name jone gives a table's primary key constraint.
+func (d *MSSQLDialect) primaryKeyName(tableName string) string {
+	return "PK_" + tableName
+}
+
+// mapDataType maps generic types to SQL Server types.
+// Strings use the Unicode N-types.
+func (d *MSSQLDialect) mapDataType(col *types.Column) string {
+	switch col.DataType {
+	case "varchar":
+		if col.Length > 0 {
+			return fmt.Sprintf("NVARCHAR(%d)", col.Length)
+		}
+		return "NVARCHAR(255)"
+	case "char":
+		if col.Length > 0 {
+			return fmt.Sprintf("NCHAR(%d)", col.Length)
+		}
+		return "NCHAR(1)"
+	case "int":
+		return "INT"
+	case "bigint":
+		return "BIGINT"
+	case "smallint":
+		return "SMALLINT"
+	case "float":
+		if col.Precision > 0 {
+			return fmt.Sprintf("FLOAT(%d)", col.Precision)
+		}
+		return "REAL"
+	case "double":
+		return "FLOAT(53)"
+	case "decimal":
+		p := col.Precision
+		if p == 0 {
+			p = 10
+		}
+		s := col.Scale
+		if s == 0 {
+			s = 2
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", p, s)
+	case "boolean":
+		return "BIT"
+	case "text":
+		return "NVARCHAR(MAX)"
+	case "date":
+		return "DATE"
+	case "time":
+		return "TIME"
+	case "timestamp":
+		return "DATETIME2"
+	case "uuid":
+		return "UNIQUEIDENTIFIER"
+	case "json", "jsonb":
+		return "NVARCHAR(MAX)" // JSON is stored as text and queried with the JSON functions
+	case "binary":
+		if col.Length > 0 {
+			return fmt.Sprintf("VARBINARY(%d)", col.Length)
+		}
+		return "VARBINARY(MAX)"
+	case "serial":
+		return "INT IDENTITY(1,1)"
+	case "bigserial":
+		return "BIGINT IDENTITY(1,1)"
+	default:
+		return strings.ToUpper(col.DataType)
+	}
+}
+
+// formatDefault formats a default value for SQL.
+func (d *MSSQLDialect) formatDefault(value any) string {
+	switch v := value.(type) {
+	case string:
+		return d.unicodeString(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// unicodeString returns s as an N'...' string literal.
+func (d *MSSQLDialect) unicodeString(s string) string {
+	return "N'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// AlterTableSQL generates ALTER TABLE statements for all actions.
+func (d *MSSQLDialect) AlterTableSQL(schema, tableName string, actions []*types.TableAction) []string {
+	qualifiedTable := d.QualifyTable(schema, tableName)
+	var statements []string
+	for _, action := range actions {
+		switch action.Type {
+		case types.ActionDropColumn:
+			// A column with a default constraint cannot be dropped until the constraint is.
+			statements = append(statements, d.dropDefaultIfExistsSQL(schema, qualifiedTable, tableName, action.Name))
+			statements = append(statements, d.dropColumnSQL(qualifiedTable, action.Name))
+		case types.ActionAddColumn:
+			statements = append(statements, d.addColumnSQL(qualifiedTable, tableName, action.Column))
+		case types.ActionRenameColumn:
+			statements = append(statements, d.renameColumnSQL(qualifiedTable, action.Name, action.NewName))
+		case types.ActionChangeColumnType:
+			statements = append(statements, d.changeColumnTypeSQL(qualifiedTable, action.Column))
+		case types.ActionSetColumnNotNull:
+			statements = append(statements, d.setNullabilitySQL(schema, qualifiedTable, tableName, action.Name, "NOT NULL"))
+		case types.ActionDropColumnNotNull:
+			statements = append(statements, d.setNullabilitySQL(schema, qualifiedTable, tableName, action.Name, "NULL"))
+		case types.ActionSetColumnDefault:
+			// Replace any existing default: SQL Server allows only one per column.
+			statements = append(statements, d.dropDefaultIfExistsSQL(schema, qualifiedTable, tableName, action.Name))
+			statements = append(statements, d.setColumnDefaultSQL(qualifiedTable, tableName, action.Name, action.DefaultValue))
+		case types.ActionDropColumnDefault:
+			statements = append(statements, d.dropColumnDefaultSQL(qualifiedTable, tableName, action.Name))
+		case types.ActionCreateIndex:
+			statements = append(statements, d.createIndexSQL(qualifiedTable, action.Index))
+		case types.ActionDropIndex:
+			statements = append(statements, d.dropIndexSQL(qualifiedTable, action.Index.Name))
+		case types.ActionAddForeignKey:
+			statements = append(statements, d.addForeignKeySQL(qualifiedTable, action.ForeignKey))
+		case types.ActionDropForeignKey:
+			statements = append(statements, d.dropConstraintSQL(qualifiedTable, action.ForeignKey.Name))
+		case types.ActionDropPrimary:
+			constraintName := action.Name
+			if constraintName == "" {
+				constraintName = d.primaryKeyName(tableName)
+			}
+			statements = append(statements, d.dropConstraintSQL(qualifiedTable, constraintName))
+		}
+	}
+	return statements
+}
+
+// createIndexSQL generates a CREATE INDEX statement.
+// A method of CLUSTERED or NONCLUSTERED is placed before INDEX; other methods are ignored.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) createIndexSQL(tableName string, idx *types.Index) string {
+	kind := "INDEX"
+	switch method := strings.ToUpper(idx.Method); method {
+	case "CLUSTERED", "NONCLUSTERED":
+		kind = method + " INDEX"
+	}
+	if idx.IsUnique {
+		kind = "UNIQUE " + kind
+	}
+
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		cols[i] = d.QuoteIdentifier(c)
+	}
+
+	return fmt.Sprintf("CREATE %s %s ON %s (%s);",
+		kind,
+		d.QuoteIdentifier(idx.Name),
+		tableName,
+		strings.Join(cols, ", "))
+}
+
+// dropIndexSQL generates a DROP INDEX statement. SQL Server requires the table name.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) dropIndexSQL(tableName, indexName string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;",
+		d.QuoteIdentifier(indexName),
+		tableName)
+}
+
+// addForeignKeySQL generates an ALTER TABLE ADD CONSTRAINT FOREIGN KEY statement.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) addForeignKeySQL(tableName string, fk *types.ForeignKey) string {
+	sql := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(%s)",
+		tableName,
+		d.QuoteIdentifier(fk.Name),
+		d.QuoteIdentifier(fk.Column),
+		d.QuoteIdentifier(fk.RefTable),
+		d.QuoteIdentifier(fk.RefColumn))
+
+	if fk.OnDelete != "" {
+		sql += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		sql += " ON UPDATE " + fk.OnUpdate
+	}
+	return sql + ";"
+}
+
+// dropConstraintSQL generates an ALTER TABLE DROP CONSTRAINT statement.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) dropConstraintSQL(tableName, constraintName string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;",
+		tableName,
+		d.QuoteIdentifier(constraintName))
+}
+
+// dropColumnSQL generates an ALTER TABLE DROP COLUMN statement.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) dropColumnSQL(tableName, columnName string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;",
+		tableName,
+		d.QuoteIdentifier(columnName))
+}
+
+// addColumnSQL generates an ALTER TABLE ADD statement (SQL Server has no ADD COLUMN keyword).
+// qualifiedTable should be pre-qualified; tableName is used to name constraints.
+func (d *MSSQLDialect) addColumnSQL(qualifiedTable, tableName string, column *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;",
+		qualifiedTable,
+		d.columnDefinitionSQL(tableName, column))
+}
+
+// renameColumnSQL generates an sp_rename call for a column.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) renameColumnSQL(tableName, oldName, newName string) string {
+	return fmt.Sprintf("EXEC sp_rename %s, %s, N'COLUMN';",
+		d.unicodeString(tableName+"."+d.QuoteIdentifier(oldName)),
+		d.unicodeString(newName))
+}
+
+// changeColumnTypeSQL generates an ALTER TABLE ALTER COLUMN statement.
+// ALTER COLUMN also resets nullability, so NOT NULL is repeated when set on the column.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *MSSQLDialect) changeColumnTypeSQL(tableName string, column *types.Column) string {
+	null := "NULL"
+	if column.IsNotNull {
+		null = "NOT NULL"
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s;",
+		tableName,
+		d.QuoteIdentifier(column.Name),
+		d.mapDataType(column),
+		null)
+}
+
+// setNullabilitySQL generates an ALTER TABLE ALTER COLUMN statement changing a
+// column's nullability to null ("NULL" or "NOT NULL"). SQL Server requires the
+// column type in ALTER COLUMN, so the statement reads it from
+// INFORMATION_SCHEMA.COLUMNS and runs the ALTER as dynamic SQL, failing if the
+// column doesn't exist. qualifiedTable should be pre-qualified; tableName is
+// used for the lookup.
+func (d *MSSQLDialect) setNullabilitySQL(schema, qualifiedTable, tableName, columnName, null string) string {
+	alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", qualifiedTable, d.QuoteIdentifier(columnName))
+	return fmt.Sprintf("DECLARE @column_type nvarchar(128); "+
+		"SELECT @column_type = DATA_TYPE + CASE "+
+		"WHEN DATA_TYPE IN ('text', 'ntext', 'image', 'xml') THEN '' "+
+		"WHEN CHARACTER_MAXIMUM_LENGTH = -1 THEN '(MAX)' "+
+		"WHEN CHARACTER_MAXIMUM_LENGTH IS NOT NULL THEN '(' + CAST(CHARACTER_MAXIMUM_LENGTH AS nvarchar(10)) + ')' "+
+		"WHEN DATA_TYPE IN ('decimal', 'numeric') THEN '(' + CAST(NUMERIC_PRECISION AS nvarchar(10)) + ', ' + CAST(NUMERIC_SCALE AS nvarchar(10)) + ')' "+
+		"WHEN DATA_TYPE IN ('datetime2', 'datetimeoffset', 'time') THEN '(' + CAST(DATETIME_PRECISION AS nvarchar(10)) + ')' "+
+		"ELSE '' END "+
+		"FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = %s AND TABLE_NAME = '%s' AND COLUMN_NAME = '%s'; "+
+		"IF @column_type IS NULL THROW 50000, %s, 1; "+
+		"EXEC(%s + @column_type + N' %s');",
+		d.schemaExpr(schema), tableName, columnName,
+		d.unicodeString(fmt.Sprintf("column %s of table %s not found", columnName, tableName)),
+		d.unicodeString(alter), null)
+}
+
+// setColumnDefaultSQL generates an ALTER TABLE ADD CONSTRAINT ... DEFAULT ... FOR statement.
+// qualifiedTable should be pre-qualified; tableName is used to name the constraint.
+func (d *MSSQLDialect) setColumnDefaultSQL(qualifiedTable, tableName, columnName string, defaultValue any) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s DEFAULT %s FOR %s;",
+		qualifiedTable,
+		d.QuoteIdentifier(d.defaultConstraintName(tableName, columnName)),
+		d.formatDefault(defaultValue),
+		d.QuoteIdentifier(columnName))
+}
+
+// dropColumnDefaultSQL drops the named default constraint of a column.
+// qualifiedTable should be pre-qualified; tableName is used to name the constraint.
+func (d *MSSQLDialect) dropColumnDefaultSQL(qualifiedTable, tableName, columnName string) string {
+	return d.dropConstraintSQL(qualifiedTable, d.defaultConstraintName(tableName, columnName))
+}
+
+// dropDefaultIfExistsSQL drops a column's named default constraint if there is one.
+func (d *MSSQLDialect) dropDefaultIfExistsSQL(schema, qualifiedTable, tableName, columnName string) string {
+	constraint := d.QualifyTable(schema, d.defaultConstraintName(tableName, columnName))
+	return fmt.Sprintf("IF OBJECT_ID(%s, N'D') IS NOT NULL %s",
+		d.unicodeString(constraint),
+		d.dropColumnDefaultSQL(qualifiedTable, tableName, columnName))
+}
+
+// HasTableSQL returns SQL to check if a table exists in SQL Server.
+// An empty schema means the user's default schema.
+func (d *MSSQLDialect) HasTableSQL(schema, tableName string) string {
+	return fmt.Sprintf(`SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = %s AND TABLE_NAME = '%s'`, d.schemaExpr(schema), tableName)
+}
+
+// HasColumnSQL returns SQL to check if a column exists in SQL Server.
+func (d *MSSQLDialect) HasColumnSQL(schema, tableName, columnName string) string {
+	return fmt.Sprintf(`SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = %s AND TABLE_NAME = '%s' AND COLUMN_NAME = '%s'`, d.schemaExpr(schema), tableName, columnName)
+}
+
+// schemaExpr returns a SQL expression for the schema name, defaulting to SCHEMA_NAME().
+func (d *MSSQLDialect) schemaExpr(schema string) string {
+	if schema == "" {
+		return "SCHEMA_NAME()"
+	}
+	return "'" + schema + "'"
+}
+
+// CommentColumnSQL returns SQL to add a column description (MS_Description extended property).
+// tableName should be pre-qualified (e.g., from QualifyTable); an unqualified table is assumed to be in dbo.
+func (d *MSSQLDialect) CommentColumnSQL(tableName, columnName, comment string) string {
+	schema, table := "dbo", d.unquote(tableName)
+	if parts := strings.SplitN(tableName, "].[", 2); len(parts) == 2 {
+		schema, table = d.unquote(parts[0]), d.unquote(parts[1])
+	}
+	return fmt.Sprintf("EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, "+
+		"@level0type = N'SCHEMA', @level0name = %s, "+
+		"@level1type = N'TABLE', @level1name = %s, "+
+		"@level2type = N'COLUMN', @level2name = %s;",
+		d.unicodeString(comment),
+		d.unicodeString(schema),
+		d.unicodeString(table),
+		d.unicodeString(columnName))
+}
+
+// unquote strips the brackets added by QuoteIdentifier.
+func (d *MSSQLDialect) unquote(name string) string {
+	name = strings.TrimPrefix(name, "[")
+	name = strings.TrimSuffix(name, "]")
+	return strings.ReplaceAll(name, "]]", "]")
+}
+
+// QualifyTable returns a schema-qualified table name.
+func (d *MSSQLDialect) QualifyTable(schema, tableName string) string {
+	if schema == "" {
+		return d.QuoteIdentifier(tableName)
+	}
+	return fmt.Sprintf("%s.%s", d.QuoteIdentifier(schema), d.QuoteIdentifier(tableName))
+}
+
+// --- Migration Tracking Methods ---
+
+// CreateMigrationsTableSQL returns SQL to create the migrations tracking table.
+func (d *MSSQLDialect) CreateMigrationsTableSQL(tableName string) string {
+	return fmt.Sprintf(`IF OBJECT_ID(%s, N'U') IS NULL
+CREATE TABLE %s (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(255) NOT NULL UNIQUE,
+	batch INT NOT NULL,
+	applied_at DATETIME2 DEFAULT SYSUTCDATETIME(),
+	checksum VARCHAR(64),
+	duration_ms INT,
+	applied_by NVARCHAR(255),
+	hostname NVARCHAR(255),
+	jone_version NVARCHAR(32)
+);`, d.unicodeString(d.QuoteIdentifier(tableName)), d.QuoteIdentifier(tableName))
+}
+
+// RecordMigrationSQL returns parameterized SQL to record a migration and how it was applied.
+func (d *MSSQLDialect) RecordMigrationSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7);",
+		d.QuoteIdentifier(tableName))
+}
+
+// DeleteMigrationSQL returns parameterized SQL to remove a migration record.
+func (d *MSSQLDialect) DeleteMigrationSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE name = @p1;",
+		d.QuoteIdentifier(tableName))
+}
+
+// CreateMigrationLogTableSQL returns SQL to create the migration history table.
+func (d *MSSQLDialect) CreateMigrationLogTableSQL(logTable string) string {
+	return fmt.Sprintf(`IF OBJECT_ID(%s, N'U') IS NULL
+CREATE TABLE %s (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(255) NOT NULL,
+	direction VARCHAR(4) NOT NULL,
+	batch INT,
+	status VARCHAR(16) NOT NULL,
+	duration_ms INT,
+	applied_by NVARCHAR(255),
+	hostname NVARCHAR(255),
+	jone_version NVARCHAR(32),
+	error NVARCHAR(MAX),
+	created_at DATETIME2 DEFAULT SYSUTCDATETIME()
+);`, d.unicodeString(d.QuoteIdentifier(logTable)), d.QuoteIdentifier(logTable))
+}
+
+// InsertMigrationLogSQL returns parameterized SQL to append a history entry.
+func (d *MSSQLDialect) InsertMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9);",
+		d.QuoteIdentifier(logTable))
+}
+
+// GetMigrationLogSQL returns SQL to get every history entry ordered by id.
+func (d *MSSQLDialect) GetMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(logTable))
+}
+
+// mssqlScripts describes the SQL Server script syntax for SQL file migrations.
+var mssqlScripts = splitStyle{batchSeparator: true}
+
+// mssqlLiterals describes SQL Server bind parameters and literals for migrate:sql.
+var mssqlLiterals = literalStyle{
+	placeholder:  placeholderAtP,
+	stringPrefix: "N",
+	boolsAsInts:  true,
+	bytes: func(b []byte) string {
+		return "0x" + hex.EncodeToString(b)
+	},
+}
+
+// SupportsTransactionalDDL reports whether DDL can be rolled back.
+// SQL Server rolls back DDL with the rest of a transaction.
+func (d *MSSQLDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// BeginTransactionSQL returns the statement that starts a transaction.
+func (d *MSSQLDialect) BeginTransactionSQL() string {
+	return "BEGIN TRANSACTION;"
+}
+
+// CommitTransactionSQL returns the statement that commits a transaction.
+func (d *MSSQLDialect) CommitTransactionSQL() string {
+	return "COMMIT TRANSACTION;"
+}
+
+// InsertMigrationScriptSQL returns parameterized SQL that records a migration
+// in the batch after the current last one plus an offset.
+func (d *MSSQLDialect) InsertMigrationScriptSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum) SELECT @p1, COALESCE(MAX(batch), 0) + @p2, @p3 FROM %s;",
+		d.QuoteIdentifier(tableName), d.QuoteIdentifier(tableName))
+}
+
+// SplitStatements returns the batches in script. Only a line holding GO ends
+// a batch, as in sqlcmd; semicolons don't, so procedure bodies stay whole.
+func (d *MSSQLDialect) SplitStatements(script string) []string {
+	return mssqlScripts.split(script)
+}
+
+// Placeholder returns the bind parameter for argument n: @p1 for 1.
+func (d *MSSQLDialect) Placeholder(n int) string {
+	return mssqlLiterals.placeholder.format(n)
+}
+
+// InlineArgs returns query with its placeholders replaced by args as SQL Server literals.
+func (d *MSSQLDialect) InlineArgs(query string, args []any) (string, error) {
+	return mssqlLiterals.inline(query, args)
+}
+
+// TerminateStatement returns stmt followed by a GO line, so each statement is
+// its own batch in sqlcmd, as CREATE PROCEDURE and CREATE TRIGGER need.
+func (d *MSSQLDialect) TerminateStatement(stmt string) string {
+	return mssqlScripts.terminate(stmt)
+}
+
+// GetAppliedMigrationsSQL returns SQL to get all applied migration names ordered by id.
+func (d *MSSQLDialect) GetAppliedMigrationsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name FROM %s ORDER BY id;",
+		d.QuoteIdentifier(tableName))
+}
+
+// AddMigrationsColumnSQL returns SQL to add a column to the migrations tracking table.
+func (d *MSSQLDialect) AddMigrationsColumnSQL(tableName string, col *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;",
+		d.QuoteIdentifier(tableName), d.ColumnDefinitionSQL(col))
+}
+
+// UpdateMigrationChecksumSQL returns parameterized SQL to replace a migration's checksum.
+func (d *MSSQLDialect) UpdateMigrationChecksumSQL(tableName string) string {
+	return fmt.Sprintf("UPDATE %s SET checksum = @p1 WHERE name = @p2;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationRecordsSQL returns SQL to get name, batch, checksum and applied_at of applied migrations ordered by id.
+func (d *MSSQLDialect) GetMigrationRecordsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name, batch, checksum, applied_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetLastBatchSQL returns SQL to get the highest batch number.
+func (d *MSSQLDialect) GetLastBatchSQL(tableName string) string {
+	return fmt.Sprintf("SELECT COALESCE(MAX(batch), 0) FROM %s;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationsByBatchSQL returns parameterized SQL to get migrations for a batch.
+func (d *MSSQLDialect) GetMigrationsByBatchSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name FROM %s WHERE batch = @p1 ORDER BY id DESC;",
+		d.QuoteIdentifier(tableName))
+}
+
+// --- Locking Methods ---
+
+// TryLockSQL returns a batch that tries to take a session-owned application lock
+// without waiting. sp_getapplock returns 0 or 1 when the lock is granted.
+func (d *MSSQLDialect) TryLockSQL() string {
+	return "DECLARE @r INT; " +
+		"EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 0; " +
+		"SELECT CASE WHEN @r >= 0 THEN 1 ELSE 0 END;"
+}
+
+// UnlockSQL returns a statement that releases the application lock taken by TryLockSQL.
+func (d *MSSQLDialect) UnlockSQL() string {
+	return "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session';"
+}
+
+// --- Introspection Methods ---
+
+// DropAllObjectsSQL returns statements that drop every foreign key, view, table
+// and user-defined type in the schema (default: the user's default schema).
+// Foreign keys go first so tables can be dropped in any order.
+func (d *MSSQLDialect) DropAllObjectsSQL(q Queryer, schema string, keep []string) ([]string, error) {
+	schemaExpr := d.schemaExpr(schema)
+	var stmts []string
+
+	fks, err := queryNames(q, nil, `SELECT OBJECT_NAME(fk.parent_object_id), fk.name FROM sys.foreign_keys fk
+WHERE OBJECT_SCHEMA_NAME(fk.parent_object_id) = `+schemaExpr+` ORDER BY 1, 2`)
+	if err != nil {
+		return nil, fmt.Errorf("listing foreign keys: %w", err)
+	}
+	for _, row := range fks {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", d.QualifyTable(schema, row[0]), d.QuoteIdentifier(row[1])))
+	}
+
+	objects := []struct {
+		kind  string
+		query string
+		skip  []string
+	}{
+		{"VIEW", `SELECT v.name FROM sys.views v WHERE SCHEMA_NAME(v.schema_id) = ` + schemaExpr + ` ORDER BY v.name`, nil},
+		{"TABLE", `SELECT t.name FROM sys.tables t WHERE SCHEMA_NAME(t.schema_id) = ` + schemaExpr + ` ORDER BY t.name`, keep},
+		{"TYPE", `SELECT t.name FROM sys.types t WHERE t.is_user_defined = 1 AND SCHEMA_NAME(t.schema_id) = ` + schemaExpr + ` ORDER BY t.name`, nil},
+	}
+	for _, obj := range objects {
+		rows, err := queryNames(q, obj.skip, obj.query)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s objects: %w", strings.ToLower(obj.kind), err)
+		}
+		for _, row := range rows {
+			stmts = append(stmts, fmt.Sprintf("DROP %s IF EXISTS %s;", obj.kind, d.QualifyTable(schema, row[0])))
+		}
+	}
+	return stmts, nil
+}
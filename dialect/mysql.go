@@ -11,6 +11,11 @@ import (
 // MySQLDialect implements Dialect for MySQL.
 type MySQLDialect struct{}
 
+func init() {
+	Register("mysql", func() Dialect { return &MySQLDialect{} })
+	RegisterAlias("mariadb", "mysql")
+}
+
 // Name returns "mysql".
 func (d *MySQLDialect) Name() string {
 	return "mysql"
@@ -57,6 +62,14 @@ func (d *MySQLDialect) DropTableIfExistsSQL(schema, name string) string {
 	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.QualifyTable(schema, name))
 }
 
+// RenameTableSQL generates a RENAME TABLE statement.
+// The new name stays in the same database as the old one.
+func (d *MySQLDialect) RenameTableSQL(schema, oldName, newName string) string {
+	return fmt.Sprintf("RENAME TABLE %s TO %s;",
+		d.QualifyTable(schema, oldName),
+		d.QualifyTable(schema, newName))
+}
+
 // CreateTableIfNotExistsSQL generates a CREATE TABLE IF NOT EXISTS statement.
 func (d *MySQLDialect) CreateTableIfNotExistsSQL(table *types.Table) string {
 	var columns []string
@@ -415,28 +428,139 @@ func (d *MySQLDialect) CreateMigrationsTableSQL(tableName string) string {
 	id INT AUTO_INCREMENT PRIMARY KEY,
 	name VARCHAR(255) NOT NULL UNIQUE,
 	batch INT NOT NULL,
-	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	checksum VARCHAR(64),
+	duration_ms INT,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32)
 );`, d.QuoteIdentifier(tableName))
 }
 
 // InsertMigrationSQL returns parameterized SQL to record a migration.
+//
+// Deprecated: jone records migrations with RecordMigrationSQL, which also
+// stores how each one was applied.
 func (d *MySQLDialect) InsertMigrationSQL(tableName string) string {
 	return fmt.Sprintf("INSERT INTO %s (name, batch) VALUES (?, ?);",
 		d.QuoteIdentifier(tableName))
 }
 
+// RecordMigrationSQL returns parameterized SQL to record a migration and how it was applied.
+func (d *MySQLDialect) RecordMigrationSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (?, ?, ?, ?, ?, ?, ?);",
+		d.QuoteIdentifier(tableName))
+}
+
 // DeleteMigrationSQL returns parameterized SQL to remove a migration record.
 func (d *MySQLDialect) DeleteMigrationSQL(tableName string) string {
 	return fmt.Sprintf("DELETE FROM %s WHERE name = ?;",
 		d.QuoteIdentifier(tableName))
 }
 
+// CreateMigrationLogTableSQL returns SQL to create the migration history table.
+func (d *MySQLDialect) CreateMigrationLogTableSQL(logTable string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	direction VARCHAR(4) NOT NULL,
+	batch INT,
+	status VARCHAR(16) NOT NULL,
+	duration_ms INT,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32),
+	error TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`, d.QuoteIdentifier(logTable))
+}
+
+// InsertMigrationLogSQL returns parameterized SQL to append a history entry.
+func (d *MySQLDialect) InsertMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);",
+		d.QuoteIdentifier(logTable))
+}
+
+// GetMigrationLogSQL returns SQL to get every history entry ordered by id.
+func (d *MySQLDialect) GetMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(logTable))
+}
+
+// mysqlScripts describes the MySQL script syntax for SQL file migrations.
+var mysqlScripts = splitStyle{backslashEscapes: true, delimiterCommand: true}
+
+// mysqlLiterals describes MySQL bind parameters and literals for migrate:sql.
+var mysqlLiterals = literalStyle{
+	placeholder:      placeholderQuestion,
+	backslashEscapes: true,
+	bytes:            hexBytes,
+}
+
+// SupportsTransactionalDDL reports whether DDL can be rolled back.
+// MySQL commits implicitly before and after most DDL statements.
+func (d *MySQLDialect) SupportsTransactionalDDL() bool {
+	return false
+}
+
+// BeginTransactionSQL returns the statement that starts a transaction.
+func (d *MySQLDialect) BeginTransactionSQL() string {
+	return "START TRANSACTION;"
+}
+
+// CommitTransactionSQL returns the statement that commits a transaction.
+func (d *MySQLDialect) CommitTransactionSQL() string {
+	return "COMMIT;"
+}
+
+// InsertMigrationScriptSQL returns parameterized SQL that records a migration
+// in the batch after the current last one plus an offset.
+func (d *MySQLDialect) InsertMigrationScriptSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum) SELECT ?, COALESCE(MAX(batch), 0) + ?, ? FROM %s;",
+		d.QuoteIdentifier(tableName), d.QuoteIdentifier(tableName))
+}
+
+// SplitStatements returns the statements in script. A DELIMITER line changes
+// the terminator, as in the mysql client, so procedure bodies can contain ";".
+func (d *MySQLDialect) SplitStatements(script string) []string {
+	return mysqlScripts.split(script)
+}
+
+// InlineArgs returns query with its placeholders replaced by args as MySQL literals.
+func (d *MySQLDialect) InlineArgs(query string, args []any) (string, error) {
+	return mysqlLiterals.inline(query, args)
+}
+
+// TerminateStatement returns stmt ended with ";", or between DELIMITER lines,
+// as the mysql client needs for procedure and trigger bodies holding ";".
+func (d *MySQLDialect) TerminateStatement(stmt string) string {
+	return mysqlScripts.terminate(stmt)
+}
+
 // GetAppliedMigrationsSQL returns SQL to get all applied migration names ordered by id.
 func (d *MySQLDialect) GetAppliedMigrationsSQL(tableName string) string {
 	return fmt.Sprintf("SELECT name FROM %s ORDER BY id;",
 		d.QuoteIdentifier(tableName))
 }
 
+// AddMigrationsColumnSQL returns SQL to add a column to the migrations tracking table.
+func (d *MySQLDialect) AddMigrationsColumnSQL(tableName string, col *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;",
+		d.QuoteIdentifier(tableName), d.ColumnDefinitionSQL(col))
+}
+
+// UpdateMigrationChecksumSQL returns parameterized SQL to replace a migration's checksum.
+func (d *MySQLDialect) UpdateMigrationChecksumSQL(tableName string) string {
+	return fmt.Sprintf("UPDATE %s SET checksum = ? WHERE name = ?;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationRecordsSQL returns SQL to get name, batch, checksum and applied_at of applied migrations ordered by id.
+func (d *MySQLDialect) GetMigrationRecordsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name, batch, checksum, applied_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(tableName))
+}
+
 // GetLastBatchSQL returns SQL to get the highest batch number.
 func (d *MySQLDialect) GetLastBatchSQL(tableName string) string {
 	return fmt.Sprintf("SELECT COALESCE(MAX(batch), 0) FROM %s;",
@@ -448,3 +572,46 @@ func (d *MySQLDialect) GetMigrationsByBatchSQL(tableName string) string {
 	return fmt.Sprintf("SELECT name FROM %s WHERE batch = ? ORDER BY id DESC;",
 		d.QuoteIdentifier(tableName))
 }
+
+// --- Locking Methods ---
+
+// TryLockSQL returns a query that tries to take a named lock without waiting.
+func (d *MySQLDialect) TryLockSQL() string {
+	return "SELECT COALESCE(GET_LOCK(?, 0), 0);"
+}
+
+// UnlockSQL returns a query that releases the named lock taken by TryLockSQL.
+func (d *MySQLDialect) UnlockSQL() string {
+	return "SELECT RELEASE_LOCK(?);"
+}
+
+// --- Introspection Methods ---
+
+// DropAllObjectsSQL returns statements that drop every view and table in the
+// schema (default: the connected database), with foreign key checks disabled.
+func (d *MySQLDialect) DropAllObjectsSQL(q Queryer, schema string, keep []string) ([]string, error) {
+	schemaExpr, args := "DATABASE()", []any{}
+	if schema != "" {
+		schemaExpr, args = "?", []any{schema}
+	}
+
+	rows, err := queryNames(q, keep,
+		"SELECT table_name, table_type FROM information_schema.tables WHERE table_schema = "+schemaExpr+" ORDER BY table_type DESC, table_name",
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	stmts := []string{"SET FOREIGN_KEY_CHECKS = 0;"}
+	for _, row := range rows {
+		kind := "TABLE"
+		if row[1] == "VIEW" {
+			kind = "VIEW"
+		}
+		stmts = append(stmts, fmt.Sprintf("DROP %s IF EXISTS %s;", kind, d.QualifyTable(schema, row[0])))
+	}
+	return append(stmts, "SET FOREIGN_KEY_CHECKS = 1;"), nil
+}
@@ -61,3 +61,13 @@ func TestMySQLDialect_FormatDSN(t *testing.T) {
 		})
 	}
 }
+
+func TestMySQLDialect_LockSQL(t *testing.T) {
+	d := &MySQLDialect{}
+	if got, want := d.TryLockSQL(), "SELECT COALESCE(GET_LOCK(?, 0), 0);"; got != want {
+		t.Errorf("TryLockSQL() = %q, want %q", got, want)
+	}
+	if got, want := d.UnlockSQL(), "SELECT RELEASE_LOCK(?);"; got != want {
+		t.Errorf("UnlockSQL() = %q, want %q", got, want)
+	}
+}
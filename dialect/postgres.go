@@ -1,6 +1,7 @@
 package dialect
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,12 @@ import (
 // PostgresDialect implements Dialect for PostgreSQL.
 type PostgresDialect struct{}
 
+func init() {
+	Register("postgresql", func() Dialect { return &PostgresDialect{} })
+	RegisterAlias("postgres", "postgresql")
+	RegisterAlias("pg", "postgresql")
+}
+
 // Name returns "postgresql".
 func (d *PostgresDialect) Name() string {
 	return "postgresql"
@@ -60,6 +67,13 @@ func (d *PostgresDialect) DropTableIfExistsSQL(schema, name string) string {
 	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.QualifyTable(schema, name))
 }
 
+// RenameTableSQL generates an ALTER TABLE RENAME TO statement.
+func (d *PostgresDialect) RenameTableSQL(schema, oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+		d.QualifyTable(schema, oldName),
+		d.QuoteIdentifier(newName))
+}
+
 // CreateTableIfNotExistsSQL generates a CREATE TABLE IF NOT EXISTS statement.
 func (d *PostgresDialect) CreateTableIfNotExistsSQL(table *types.Table) string {
 	var columns []string
@@ -410,28 +424,144 @@ func (d *PostgresDialect) CreateMigrationsTableSQL(tableName string) string {
 	id SERIAL PRIMARY KEY,
 	name VARCHAR(255) NOT NULL UNIQUE,
 	batch INTEGER NOT NULL,
-	applied_at TIMESTAMP DEFAULT NOW()
+	applied_at TIMESTAMP DEFAULT NOW(),
+	checksum VARCHAR(64),
+	duration_ms INTEGER,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32)
 );`, d.QuoteIdentifier(tableName))
 }
 
 // InsertMigrationSQL returns parameterized SQL to record a migration.
+//
+// Deprecated: jone records migrations with RecordMigrationSQL, which also
+// stores how each one was applied.
 func (d *PostgresDialect) InsertMigrationSQL(tableName string) string {
 	return fmt.Sprintf(`INSERT INTO "public".%s (name, batch) VALUES ($1, $2);`,
 		d.QuoteIdentifier(tableName))
 }
 
+// RecordMigrationSQL returns parameterized SQL to record a migration and how it was applied.
+func (d *PostgresDialect) RecordMigrationSQL(tableName string) string {
+	return fmt.Sprintf(`INSERT INTO "public".%s (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		d.QuoteIdentifier(tableName))
+}
+
 // DeleteMigrationSQL returns parameterized SQL to remove a migration record.
 func (d *PostgresDialect) DeleteMigrationSQL(tableName string) string {
 	return fmt.Sprintf(`DELETE FROM "public".%s WHERE name = $1;`,
 		d.QuoteIdentifier(tableName))
 }
 
+// CreateMigrationLogTableSQL returns SQL to create the migration history table.
+func (d *PostgresDialect) CreateMigrationLogTableSQL(logTable string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "public".%s (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	direction VARCHAR(4) NOT NULL,
+	batch INTEGER,
+	status VARCHAR(16) NOT NULL,
+	duration_ms INTEGER,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32),
+	error TEXT,
+	created_at TIMESTAMP DEFAULT NOW()
+);`, d.QuoteIdentifier(logTable))
+}
+
+// InsertMigrationLogSQL returns parameterized SQL to append a history entry.
+func (d *PostgresDialect) InsertMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf(`INSERT INTO "public".%s (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		d.QuoteIdentifier(logTable))
+}
+
+// GetMigrationLogSQL returns SQL to get every history entry ordered by id.
+func (d *PostgresDialect) GetMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf(`SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM "public".%s ORDER BY id;`,
+		d.QuoteIdentifier(logTable))
+}
+
+// postgresScripts describes the PostgreSQL script syntax for SQL file migrations.
+var postgresScripts = splitStyle{dollarQuotes: true}
+
+// postgresLiterals describes PostgreSQL bind parameters and literals for migrate:sql.
+var postgresLiterals = literalStyle{
+	placeholder: placeholderDollar,
+	bytes: func(b []byte) string {
+		return `'\x` + hex.EncodeToString(b) + `'::bytea`
+	},
+}
+
+// SupportsTransactionalDDL reports whether DDL can be rolled back.
+// PostgreSQL rolls back DDL with the rest of a transaction.
+func (d *PostgresDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// BeginTransactionSQL returns the statement that starts a transaction.
+func (d *PostgresDialect) BeginTransactionSQL() string {
+	return "BEGIN;"
+}
+
+// CommitTransactionSQL returns the statement that commits a transaction.
+func (d *PostgresDialect) CommitTransactionSQL() string {
+	return "COMMIT;"
+}
+
+// InsertMigrationScriptSQL returns parameterized SQL that records a migration
+// in the batch after the current last one plus an offset.
+func (d *PostgresDialect) InsertMigrationScriptSQL(tableName string) string {
+	return fmt.Sprintf(`INSERT INTO "public".%s (name, batch, checksum) SELECT $1, COALESCE(MAX(batch), 0) + $2, $3 FROM "public".%s;`,
+		d.QuoteIdentifier(tableName), d.QuoteIdentifier(tableName))
+}
+
+// SplitStatements returns the statements in script. $$ and $tag$ quoted
+// function bodies are kept whole.
+func (d *PostgresDialect) SplitStatements(script string) []string {
+	return postgresScripts.split(script)
+}
+
+// Placeholder returns the bind parameter for argument n: $1 for 1.
+func (d *PostgresDialect) Placeholder(n int) string {
+	return postgresLiterals.placeholder.format(n)
+}
+
+// InlineArgs returns query with its placeholders replaced by args as PostgreSQL literals.
+func (d *PostgresDialect) InlineArgs(query string, args []any) (string, error) {
+	return postgresLiterals.inline(query, args)
+}
+
+// TerminateStatement returns stmt ended with ";".
+func (d *PostgresDialect) TerminateStatement(stmt string) string {
+	return postgresScripts.terminate(stmt)
+}
+
 // GetAppliedMigrationsSQL returns SQL to get all applied migration names ordered by id.
 func (d *PostgresDialect) GetAppliedMigrationsSQL(tableName string) string {
 	return fmt.Sprintf(`SELECT name FROM "public".%s ORDER BY id;`,
 		d.QuoteIdentifier(tableName))
 }
 
+// AddMigrationsColumnSQL returns SQL to add a column to the migrations tracking table.
+func (d *PostgresDialect) AddMigrationsColumnSQL(tableName string, col *types.Column) string {
+	return fmt.Sprintf(`ALTER TABLE "public".%s ADD COLUMN %s;`,
+		d.QuoteIdentifier(tableName), d.ColumnDefinitionSQL(col))
+}
+
+// UpdateMigrationChecksumSQL returns parameterized SQL to replace a migration's checksum.
+func (d *PostgresDialect) UpdateMigrationChecksumSQL(tableName string) string {
+	return fmt.Sprintf(`UPDATE "public".%s SET checksum = $1 WHERE name = $2;`,
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationRecordsSQL returns SQL to get name, batch, checksum and applied_at of applied migrations ordered by id.
+func (d *PostgresDialect) GetMigrationRecordsSQL(tableName string) string {
+	return fmt.Sprintf(`SELECT name, batch, checksum, applied_at FROM "public".%s ORDER BY id;`,
+		d.QuoteIdentifier(tableName))
+}
+
 // GetLastBatchSQL returns SQL to get the highest batch number.
 func (d *PostgresDialect) GetLastBatchSQL(tableName string) string {
 	return fmt.Sprintf(`SELECT COALESCE(MAX(batch), 0) FROM "public".%s;`,
@@ -443,3 +573,56 @@ func (d *PostgresDialect) GetMigrationsByBatchSQL(tableName string) string {
 	return fmt.Sprintf(`SELECT name FROM "public".%s WHERE batch = $1 ORDER BY id DESC;`,
 		d.QuoteIdentifier(tableName))
 }
+
+// --- Locking Methods ---
+
+// TryLockSQL returns a query that tries to take an advisory lock keyed on the lock name.
+func (d *PostgresDialect) TryLockSQL() string {
+	return `SELECT CASE WHEN pg_try_advisory_lock(hashtext($1)) THEN 1 ELSE 0 END;`
+}
+
+// UnlockSQL returns a query that releases the advisory lock taken by TryLockSQL.
+func (d *PostgresDialect) UnlockSQL() string {
+	return `SELECT pg_advisory_unlock(hashtext($1));`
+}
+
+// --- Introspection Methods ---
+
+// DropAllObjectsSQL returns statements that drop every view, materialized view,
+// table, domain, enum and composite type in the schema (default "public").
+func (d *PostgresDialect) DropAllObjectsSQL(q Queryer, schema string, keep []string) ([]string, error) {
+	if schema == "" {
+		schema = "public"
+	}
+
+	queries := []struct {
+		kind  string
+		query string
+		skip  []string
+	}{
+		{"VIEW", `SELECT table_name FROM information_schema.views WHERE table_schema = $1 ORDER BY table_name`, nil},
+		{"MATERIALIZED VIEW", `SELECT matviewname FROM pg_matviews WHERE schemaname = $1 ORDER BY matviewname`, nil},
+		{"TABLE", `SELECT tablename FROM pg_tables WHERE schemaname = $1 ORDER BY tablename`, keep},
+		{"DOMAIN", `SELECT t.typname FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+WHERE n.nspname = $1 AND t.typtype = 'd'
+ORDER BY t.typname`, nil},
+		{"TYPE", `SELECT t.typname FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+LEFT JOIN pg_class c ON c.oid = t.typrelid
+WHERE n.nspname = $1 AND (t.typtype = 'e' OR (t.typtype = 'c' AND c.relkind = 'c'))
+ORDER BY t.typname`, nil},
+	}
+
+	var stmts []string
+	for _, qq := range queries {
+		rows, err := queryNames(q, qq.skip, qq.query, schema)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s objects in schema %s: %w", strings.ToLower(qq.kind), schema, err)
+		}
+		for _, row := range rows {
+			stmts = append(stmts, fmt.Sprintf("DROP %s IF EXISTS %s CASCADE;", qq.kind, d.QualifyTable(schema, row[0])))
+		}
+	}
+	return stmts, nil
+}
@@ -334,3 +334,70 @@ func TestPostgresDialect_FormatDSN(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresDialect_LockSQL(t *testing.T) {
+	var d Dialect = &PostgresDialect{}
+	locker, ok := d.(Locker)
+	if !ok {
+		t.Fatal("PostgresDialect does not implement Locker")
+	}
+	if got, want := locker.TryLockSQL(), "SELECT CASE WHEN pg_try_advisory_lock(hashtext($1)) THEN 1 ELSE 0 END;"; got != want {
+		t.Errorf("TryLockSQL() = %q, want %q", got, want)
+	}
+	if got, want := locker.UnlockSQL(), "SELECT pg_advisory_unlock(hashtext($1));"; got != want {
+		t.Errorf("UnlockSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestPostgresDialect_MigrationChecksumSQL(t *testing.T) {
+	d := &PostgresDialect{}
+
+	if create := d.CreateMigrationsTableSQL("jone_migrations"); !strings.Contains(create, "checksum VARCHAR(64)") {
+		t.Errorf("checksum column missing, got: %s", create)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"add column", d.AddMigrationsColumnSQL("jone_migrations", &types.Column{Name: "checksum", DataType: "varchar", Length: 64}),
+			`ALTER TABLE "public"."jone_migrations" ADD COLUMN "checksum" VARCHAR(64);`},
+		{"update", d.UpdateMigrationChecksumSQL("jone_migrations"),
+			`UPDATE "public"."jone_migrations" SET checksum = $1 WHERE name = $2;`},
+		{"records", d.GetMigrationRecordsSQL("jone_migrations"),
+			`SELECT name, batch, checksum, applied_at FROM "public"."jone_migrations" ORDER BY id;`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestPostgresDialect_MigrationLogSQL(t *testing.T) {
+	d := &PostgresDialect{}
+
+	create := d.CreateMigrationLogTableSQL("jone_migrations_log")
+	for _, col := range []string{`"public"."jone_migrations_log"`, "direction VARCHAR(4)", "status VARCHAR(16)", "error TEXT"} {
+		if !strings.Contains(create, col) {
+			t.Errorf("CreateMigrationLogTableSQL() missing %q, got: %s", col, create)
+		}
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"insert", d.InsertMigrationLogSQL("jone_migrations_log"),
+			`INSERT INTO "public"."jone_migrations_log" (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`},
+		{"select", d.GetMigrationLogSQL("jone_migrations_log"),
+			`SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM "public"."jone_migrations_log" ORDER BY id;`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
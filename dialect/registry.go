@@ -0,0 +1,103 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownDialect is returned by GetDialect when no dialect is registered under a name.
+var ErrUnknownDialect = errors.New("unknown dialect")
+
+var (
+	registryMu sync.RWMutex
+	factories  = make(map[string]func() Dialect)
+	aliases    = make(map[string]string)
+)
+
+// Register makes a dialect available under the given name (the value of Config.Client).
+// Built-in dialects register themselves; third-party modules can call Register from an
+// init function to plug in their own. Names are case-insensitive.
+// Register panics if factory is nil or the name is already taken.
+func Register(name string, factory func() Dialect) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	key := normalizeName(name)
+	if factory == nil {
+		panic("dialect: Register factory is nil for " + name)
+	}
+	if key == "" {
+		panic("dialect: Register called with an empty name")
+	}
+	if _, dup := factories[key]; dup {
+		panic("dialect: Register called twice for " + name)
+	}
+	if _, dup := aliases[key]; dup {
+		panic("dialect: Register name " + name + " is already an alias")
+	}
+	factories[key] = factory
+}
+
+// RegisterAlias makes an already registered dialect available under another name
+// (e.g., "pg" for "postgresql").
+// RegisterAlias panics if name is not registered or alias is already taken.
+func RegisterAlias(alias, name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	aliasKey, nameKey := normalizeName(alias), normalizeName(name)
+	if _, ok := factories[nameKey]; !ok {
+		panic("dialect: RegisterAlias target " + name + " is not registered")
+	}
+	if _, dup := factories[aliasKey]; dup {
+		panic("dialect: RegisterAlias alias " + alias + " is already a dialect name")
+	}
+	if _, dup := aliases[aliasKey]; dup {
+		panic("dialect: RegisterAlias called twice for " + alias)
+	}
+	aliases[aliasKey] = nameKey
+}
+
+// GetDialect returns a new instance of the dialect registered under name or one of its aliases.
+// Unknown names return an error wrapping ErrUnknownDialect that lists the known names.
+func GetDialect(name string) (Dialect, error) {
+	registryMu.RLock()
+	key := normalizeName(name)
+	if target, ok := aliases[key]; ok {
+		key = target
+	}
+	factory, ok := factories[key]
+	registryMu.RUnlock()
+
+	if !ok {
+		if key == "" {
+			return nil, fmt.Errorf("%w: no client configured (known: %s)", ErrUnknownDialect, strings.Join(Dialects(), ", "))
+		}
+		return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownDialect, name, strings.Join(Dialects(), ", "))
+	}
+	return factory(), nil
+}
+
+// Dialects returns the sorted list of registered dialect names and aliases.
+func Dialects() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(factories)+len(aliases))
+	for name := range factories {
+		names = append(names, name)
+	}
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizeName makes dialect lookups case-insensitive and ignores surrounding whitespace.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,100 @@
+package dialect
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetDialect_BuiltinsAndAliases(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"postgresql", "postgresql"},
+		{"postgres", "postgresql"},
+		{"pg", "postgresql"},
+		{"PostgreSQL", "postgresql"},
+		{"mysql", "mysql"},
+		{"mariadb", "mysql"},
+		{"sqlite", "sqlite"},
+		{"sqlite3", "sqlite"},
+		{"mssql", "mssql"},
+		{"sqlserver", "mssql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := GetDialect(tt.name)
+			if err != nil {
+				t.Fatalf("GetDialect(%q) error: %v", tt.name, err)
+			}
+			if got := d.Name(); got != tt.want {
+				t.Errorf("GetDialect(%q).Name() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDialect_Unknown(t *testing.T) {
+	for _, name := range []string{"postgress", ""} {
+		_, err := GetDialect(name)
+		if err == nil {
+			t.Fatalf("GetDialect(%q) expected error", name)
+		}
+		if !errors.Is(err, ErrUnknownDialect) {
+			t.Errorf("GetDialect(%q) error = %v, want ErrUnknownDialect", name, err)
+		}
+		if !strings.Contains(err.Error(), "postgresql") || !strings.Contains(err.Error(), "mysql") {
+			t.Errorf("GetDialect(%q) error does not list known names: %v", name, err)
+		}
+	}
+}
+
+// customDialect is a third-party dialect used to exercise Register.
+type customDialect struct {
+	PostgresDialect
+}
+
+func (d *customDialect) Name() string { return "cockroach" }
+
+func TestRegister_CustomDialect(t *testing.T) {
+	Register("cockroach", func() Dialect { return &customDialect{} })
+	RegisterAlias("crdb", "cockroach")
+
+	d, err := GetDialect("crdb")
+	if err != nil {
+		t.Fatalf("GetDialect(crdb) error: %v", err)
+	}
+	if got := d.Name(); got != "cockroach" {
+		t.Errorf("Name() = %q, want %q", got, "cockroach")
+	}
+
+	found := false
+	for _, name := range Dialects() {
+		if name == "crdb" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Dialects() = %v, want it to include alias crdb", Dialects())
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when registering a duplicate name")
+		}
+	}()
+	Register("postgresql", func() Dialect { return &PostgresDialect{} })
+}
+
+func TestRegisterAlias_UnknownTargetPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when aliasing an unregistered dialect")
+		}
+	}()
+	RegisterAlias("nope", "does-not-exist")
+}
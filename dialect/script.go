@@ -0,0 +1,168 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// placeholderStyle is the bind parameter syntax a dialect's driver expects.
+type placeholderStyle int
+
+const (
+	placeholderDollar   placeholderStyle = iota // $1, $2, ...
+	placeholderQuestion                         // ?, ?, ...
+	placeholderAtP                              // @p1, @p2, ...
+)
+
+// format returns the bind parameter for argument n, counting from 1.
+func (ps placeholderStyle) format(n int) string {
+	switch ps {
+	case placeholderDollar:
+		return "$" + strconv.Itoa(n)
+	case placeholderAtP:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// literalStyle describes how a dialect writes bind parameters and literals.
+// It backs the Scripter implementations.
+type literalStyle struct {
+	placeholder      placeholderStyle
+	stringPrefix     string // e.g. "N" for SQL Server Unicode strings
+	backslashEscapes bool   // Backslash is an escape character inside strings (MySQL)
+	boolsAsInts      bool   // Write booleans as 1 and 0
+	bytes            func(b []byte) string
+}
+
+// inline returns query with its placeholders replaced by args as SQL literals.
+// Placeholders inside string literals, quoted identifiers and line comments are
+// left alone.
+func (st literalStyle) inline(query string, args []any) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+
+	var b strings.Builder
+	next := 0 // Next argument for positional "?" placeholders
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				i = len(query)
+				continue
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '?' && st.placeholder == placeholderQuestion:
+			if next >= len(args) {
+				return "", fmt.Errorf("query has more placeholders than the %d argument(s) given", len(args))
+			}
+			lit, err := st.literal(args[next])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(lit)
+			next++
+		case c == '$' && st.placeholder == placeholderDollar,
+			c == '@' && st.placeholder == placeholderAtP && strings.HasPrefix(query[i:], "@p"):
+			start := i + 1
+			if c == '@' {
+				start++
+			}
+			end := start
+			for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+				end++
+			}
+			if end == start {
+				b.WriteByte(c)
+				continue
+			}
+			n, _ := strconv.Atoi(query[start:end])
+			if n < 1 || n > len(args) {
+				return "", fmt.Errorf("placeholder %s has no argument (%d given)", query[i:end], len(args))
+			}
+			lit, err := st.literal(args[n-1])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(lit)
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// literal renders v as a SQL literal.
+func (st literalStyle) literal(v any) (string, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		v = value
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return st.quote(v), nil
+	case []byte:
+		if v == nil {
+			return "NULL", nil
+		}
+		return st.bytes(v), nil
+	case bool:
+		switch {
+		case st.boolsAsInts && v:
+			return "1", nil
+		case st.boolsAsInts:
+			return "0", nil
+		case v:
+			return "TRUE", nil
+		default:
+			return "FALSE", nil
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return st.quote(v.UTC().Format("2006-01-02 15:04:05.999999")), nil
+	default:
+		return "", fmt.Errorf("cannot write %T as a SQL literal", v)
+	}
+}
+
+// quote returns s as a string literal.
+func (st literalStyle) quote(s string) string {
+	if st.backslashEscapes {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return st.stringPrefix + "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// hexBytes writes b as X'...', understood by MySQL and SQLite.
+func hexBytes(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
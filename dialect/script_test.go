@@ -0,0 +1,84 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInlineArgs(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		d     Scripter
+		query string
+		args  []any
+		want  string
+	}{
+		{
+			"postgres", &PostgresDialect{},
+			"INSERT INTO t (a, b, c) VALUES ($1, $2, $3)", []any{"it's", 42, true},
+			"INSERT INTO t (a, b, c) VALUES ('it''s', 42, TRUE)",
+		},
+		{
+			"postgres reuses numbered placeholders", &PostgresDialect{},
+			"UPDATE t SET a = $1 WHERE b = $1 AND c = '$2'", []any{nil, "x"},
+			"UPDATE t SET a = NULL WHERE b = NULL AND c = '$2'",
+		},
+		{
+			"postgres bytes", &PostgresDialect{},
+			"INSERT INTO t (b) VALUES ($1)", []any{[]byte{0xca, 0xfe}},
+			`INSERT INTO t (b) VALUES ('\xcafe'::bytea)`,
+		},
+		{
+			"mysql escapes backslashes", &MySQLDialect{},
+			"INSERT INTO t (a, b) VALUES (?, ?) -- why?", []any{`a\b`, 1.5},
+			`INSERT INTO t (a, b) VALUES ('a\\b', 1.5) -- why?`,
+		},
+		{
+			"sqlite", &SQLiteDialect{},
+			"UPDATE t SET a = ?, b = ? WHERE c = '?'", []any{false, at},
+			"UPDATE t SET a = 0, b = '2026-01-02 03:04:05' WHERE c = '?'",
+		},
+		{
+			"mssql", &MSSQLDialect{},
+			"INSERT INTO t (a, b) VALUES (@p1, @p2)", []any{"é", []byte{1}},
+			"INSERT INTO t (a, b) VALUES (N'é', 0x01)",
+		},
+		{
+			"no args", &PostgresDialect{},
+			"CREATE FUNCTION f() RETURNS int AS $$ SELECT $1 $$", nil,
+			"CREATE FUNCTION f() RETURNS int AS $$ SELECT $1 $$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.InlineArgs(tt.query, tt.args)
+			if err != nil {
+				t.Fatalf("InlineArgs() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InlineArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineArgs_Errors(t *testing.T) {
+	if _, err := (&PostgresDialect{}).InlineArgs("SELECT $2", []any{1}); err == nil {
+		t.Error("expected error for a placeholder without an argument")
+	}
+	if _, err := (&MySQLDialect{}).InlineArgs("SELECT ?, ?", []any{1}); err == nil {
+		t.Error("expected error for too few arguments")
+	}
+	if _, err := (&MySQLDialect{}).InlineArgs("SELECT ?", []any{struct{}{}}); err == nil {
+		t.Error("expected error for an unsupported type")
+	}
+}
+
+func TestInsertMigrationScriptSQL(t *testing.T) {
+	got := (&MySQLDialect{}).InsertMigrationScriptSQL("jone_migrations")
+	want := "INSERT INTO `jone_migrations` (name, batch, checksum) SELECT ?, COALESCE(MAX(batch), 0) + ?, ? FROM `jone_migrations`;"
+	if got != want {
+		t.Errorf("InsertMigrationScriptSQL() = %q, want %q", got, want)
+	}
+}
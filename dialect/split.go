@@ -0,0 +1,212 @@
+package dialect
+
+import (
+	"regexp"
+	"strings"
+)
+
+// splitStyle describes the client syntax a dialect's SQL scripts use. It backs
+// the StatementSplitter implementations.
+type splitStyle struct {
+	dollarQuotes     bool // $$ and $tag$ quote function bodies (PostgreSQL)
+	backslashEscapes bool // Backslash is an escape character inside strings (MySQL)
+	delimiterCommand bool // A DELIMITER line changes the statement terminator (MySQL)
+	batchSeparator   bool // Only a line holding GO ends a statement, not the terminator (SQL Server)
+	triggerBodies    bool // CREATE TRIGGER ... BEGIN ... END is one statement (SQLite)
+}
+
+// dollarTag matches the opening tag of a dollar-quoted string, such as $$ or $body$.
+var dollarTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// createTrigger matches the start of a CREATE TRIGGER statement, after any comments.
+var createTrigger = regexp.MustCompile(`(?is)^(\s*(--[^\n]*\n|/\*.*?\*/))*\s*CREATE\s+(TEMP\s+|TEMPORARY\s+)?TRIGGER\b`)
+
+// endsBody reports whether stmt, a CREATE TRIGGER statement so far, ends with
+// the END of its body. BEGIN and CASE open a block that END closes, so the
+// END of a CASE expression inside the body doesn't end it. Words inside
+// strings, quoted names and comments are ignored.
+func (st splitStyle) endsBody(stmt string) bool {
+	depth := 0
+	inBody := false
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				return inBody && depth <= 0
+			}
+			i += end
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				return false
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			i = st.skipQuoted(stmt, i)
+		case isWordByte(c):
+			j := i
+			for j < len(stmt) && isWordByte(stmt[j]) {
+				j++
+			}
+			switch word := stmt[i:j]; {
+			case strings.EqualFold(word, "BEGIN"):
+				inBody = true
+				depth++
+			case strings.EqualFold(word, "CASE"):
+				depth++
+			case strings.EqualFold(word, "END"):
+				depth--
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	return inBody && depth <= 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// split returns the statements in script without their terminators.
+// Terminators inside string literals, quoted identifiers, comments and
+// dollar-quoted bodies don't end a statement. Statements holding only comments
+// are dropped.
+func (st splitStyle) split(script string) []string {
+	var stmts []string
+	delimiter := ";"
+	start := 0       // Start of the current statement
+	hasCode := false // The current statement has more than comments
+	lineStart := true
+
+	flush := func(end int) {
+		if hasCode {
+			stmts = append(stmts, strings.TrimSpace(script[start:end]))
+		}
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); {
+		if lineStart {
+			lineStart = false
+			line, _, _ := strings.Cut(script[i:], "\n")
+			next := i + len(line)
+			if next < len(script) {
+				next++ // Past the newline
+			}
+			fields := strings.Fields(line)
+			switch {
+			case st.delimiterCommand && len(fields) == 2 && strings.EqualFold(fields[0], "DELIMITER"):
+				flush(i)
+				delimiter = fields[1]
+				i, start = next, next
+				lineStart = true
+				continue
+			case st.batchSeparator && len(fields) == 1 && strings.EqualFold(fields[0], "GO"):
+				flush(i)
+				i, start = next, next
+				lineStart = true
+				continue
+			}
+		}
+
+		c := script[i]
+		switch {
+		case !st.batchSeparator && strings.HasPrefix(script[i:], delimiter) && st.ends(script[start:i]):
+			flush(i)
+			i += len(delimiter)
+			start = i
+		case c == '\n':
+			lineStart = true
+			i++
+		case strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 4
+			}
+		case c == '\'' || c == '"' || c == '`':
+			hasCode = true
+			i = st.skipQuoted(script, i)
+		case c == '$' && st.dollarQuotes && dollarTag.MatchString(script[i:]):
+			hasCode = true
+			tag := dollarTag.FindString(script[i:])
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+		default:
+			if c != ' ' && c != '\t' && c != '\r' {
+				hasCode = true
+			}
+			i++
+		}
+	}
+	flush(len(script))
+	return stmts
+}
+
+// terminate returns stmt as a script run by the dialect's client needs it: with
+// its terminator, followed by a GO line where batches are separated, or
+// between DELIMITER lines where its body holds terminators that would
+// otherwise end it early.
+func (st splitStyle) terminate(stmt string) string {
+	stmt = strings.TrimSpace(stmt)
+	switch {
+	case st.batchSeparator:
+		return stmt + "\nGO"
+	case st.delimiterCommand && len(st.split(stmt)) > 1:
+		delimiter := "//"
+		for _, d := range []string{"$$", ";;"} {
+			if !strings.Contains(stmt, delimiter) {
+				break
+			}
+			delimiter = d
+		}
+		return "DELIMITER " + delimiter + "\n" + strings.TrimSuffix(stmt, ";") + delimiter + "\nDELIMITER ;"
+	case strings.HasSuffix(stmt, ";"):
+		return stmt
+	default:
+		return stmt + ";"
+	}
+}
+
+// ends reports whether a terminator after stmt ends it, which it does except
+// inside the body of a trigger.
+func (st splitStyle) ends(stmt string) bool {
+	if !st.triggerBodies || !createTrigger.MatchString(stmt) {
+		return true
+	}
+	return st.endsBody(stmt)
+}
+
+// skipQuoted returns the index just past the quoted string or identifier that
+// starts at script[i]. A doubled quote character is part of the string.
+func (st splitStyle) skipQuoted(script string, i int) int {
+	quote := script[i]
+	for j := i + 1; j < len(script); j++ {
+		switch {
+		case script[j] == '\\' && st.backslashEscapes && quote != '`':
+			j++
+		case script[j] == quote:
+			if j+1 < len(script) && script[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(script)
+}
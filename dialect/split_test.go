@@ -0,0 +1,111 @@
+package dialect
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      StatementSplitter
+		script string
+		want   []string
+	}{
+		{
+			"terminators in strings and comments", &PostgresDialect{},
+			"-- users; posts\nCREATE TABLE t (a text DEFAULT 'x;y');\n/* ; */ INSERT INTO \"a;b\" VALUES ('it''s;');\n",
+			[]string{"-- users; posts\nCREATE TABLE t (a text DEFAULT 'x;y')", "/* ; */ INSERT INTO \"a;b\" VALUES ('it''s;')"},
+		},
+		{
+			"comment-only tail dropped", &PostgresDialect{},
+			"SELECT 1;\n-- done\n",
+			[]string{"SELECT 1"},
+		},
+		{
+			"missing final terminator", &SQLiteDialect{},
+			"SELECT 1;\nSELECT 2",
+			[]string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			"postgres dollar-quoted body", &PostgresDialect{},
+			"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;\n" +
+				"CREATE FUNCTION g() RETURNS text AS $body$ SELECT '$$;'; $body$ LANGUAGE sql;\nSELECT $1;",
+			[]string{
+				"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql",
+				"CREATE FUNCTION g() RETURNS text AS $body$ SELECT '$$;'; $body$ LANGUAGE sql",
+				"SELECT $1",
+			},
+		},
+		{
+			"mysql delimiter", &MySQLDialect{},
+			"DROP PROCEDURE IF EXISTS p;\nDELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END//\nDELIMITER ;\nSELECT 'a\\';b';",
+			[]string{"DROP PROCEDURE IF EXISTS p", "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", "SELECT 'a\\';b'"},
+		},
+		{
+			"mysql has no dollar quotes", &MySQLDialect{},
+			"SELECT '$$'; SELECT 2;",
+			[]string{"SELECT '$$'", "SELECT 2"},
+		},
+		{
+			"sqlite trigger body", &SQLiteDialect{},
+			"CREATE TRIGGER t AFTER INSERT ON a BEGIN\n  UPDATE b SET n = n + 1;\n  DELETE FROM c;\nEND;\nSELECT 1;",
+			[]string{"CREATE TRIGGER t AFTER INSERT ON a BEGIN\n  UPDATE b SET n = n + 1;\n  DELETE FROM c;\nEND", "SELECT 1"},
+		},
+		{
+			"sqlite nested CASE in trigger body", &SQLiteDialect{},
+			"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET x = CASE WHEN 1 THEN 2 END; SELECT 'end;'; END;\nSELECT 1;",
+			[]string{"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET x = CASE WHEN 1 THEN 2 END; SELECT 'end;'; END", "SELECT 1"},
+		},
+		{
+			"mssql GO batches", &MSSQLDialect{},
+			"CREATE TABLE t (a int)\nGO\nCREATE PROCEDURE p AS BEGIN SELECT 1 END\ngo\n",
+			[]string{"CREATE TABLE t (a int)", "CREATE PROCEDURE p AS BEGIN SELECT 1 END"},
+		},
+		{
+			"mssql semicolons stay in the batch", &MSSQLDialect{},
+			"CREATE PROCEDURE p AS BEGIN SELECT 1; SELECT 2; END\nGO\nINSERT INTO t VALUES (1); INSERT INTO t VALUES (2);\n",
+			[]string{"CREATE PROCEDURE p AS BEGIN SELECT 1; SELECT 2; END", "INSERT INTO t VALUES (1); INSERT INTO t VALUES (2);"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.SplitStatements(tt.script)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitStatements() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminateStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Scripter
+		stmt string
+		want string
+	}{
+		{"postgres adds terminator", &PostgresDialect{}, "SELECT 1", "SELECT 1;"},
+		{"postgres keeps terminator", &PostgresDialect{}, "SELECT 1;\n", "SELECT 1;"},
+		{"postgres function body", &PostgresDialect{}, "CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql",
+			"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;"},
+		{"sqlite trigger body", &SQLiteDialect{}, "CREATE TRIGGER t AFTER INSERT ON a BEGIN DELETE FROM c; END",
+			"CREATE TRIGGER t AFTER INSERT ON a BEGIN DELETE FROM c; END;"},
+		{"mysql statement", &MySQLDialect{}, "SELECT 'a;b'", "SELECT 'a;b';"},
+		{"mysql procedure body", &MySQLDialect{}, "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END",
+			"DELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END//\nDELIMITER ;"},
+		{"mysql body holding the delimiter", &MySQLDialect{}, "CREATE PROCEDURE p() BEGIN SELECT 1; -- a//b\nEND",
+			"DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 1; -- a//b\nEND$$\nDELIMITER ;"},
+		{"mssql batch", &MSSQLDialect{}, "CREATE PROCEDURE p AS BEGIN SELECT 1; SELECT 2; END",
+			"CREATE PROCEDURE p AS BEGIN SELECT 1; SELECT 2; END\nGO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.TerminateStatement(tt.stmt); got != tt.want {
+				t.Errorf("TerminateStatement() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1019 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/types"
+)
+
+// SQLiteDialect implements Dialect for SQLite.
+//
+// SQLite's ALTER TABLE only supports adding, renaming and dropping columns.
+// Every other alteration (changing a type, nullability or default, adding or
+// dropping foreign keys, dropping the primary key) is applied by rebuilding
+// the table, see RebuildTableSQL.
+type SQLiteDialect struct{}
+
+func init() {
+	Register("sqlite", func() Dialect { return &SQLiteDialect{} })
+	RegisterAlias("sqlite3", "sqlite")
+}
+
+// rawDefault is a default value that is already a SQL expression, as read back
+// from PRAGMA table_info. It is emitted as-is instead of being quoted.
+type rawDefault string
+
+// sqliteFKNamePattern extracts named foreign key constraints from a CREATE TABLE statement.
+var sqliteFKNamePattern = regexp.MustCompile(`(?i)CONSTRAINT\s+"?(\w+)"?\s+FOREIGN\s+KEY\s*\(\s*"?(\w+)"?`)
+
+// Name returns "sqlite".
+func (d *SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+// DriverName returns "sqlite3" for the mattn/go-sqlite3 driver.
+func (d *SQLiteDialect) DriverName() string {
+	return "sqlite3"
+}
+
+// FormatDSN returns the database file path from the connection parameters.
+// Host, port and credentials are ignored; Database may also be a "file:" URI.
+func (d *SQLiteDialect) FormatDSN(conn config.Connection) string {
+	return conn.Database
+}
+
+// QuoteIdentifier quotes an identifier with double quotes for SQLite.
+func (d *SQLiteDialect) QuoteIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, name)
+}
+
+// CreateTableSQL generates a CREATE TABLE statement for SQLite.
+func (d *SQLiteDialect) CreateTableSQL(table *types.Table) string {
+	return d.createTableSQL("CREATE TABLE", table)
+}
+
+// RenameTableSQL generates an ALTER TABLE RENAME TO statement.
+func (d *SQLiteDialect) RenameTableSQL(schema, oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+		d.QualifyTable(schema, oldName),
+		d.QuoteIdentifier(newName))
+}
+
+// CreateTableIfNotExistsSQL generates a CREATE TABLE IF NOT EXISTS statement.
+func (d *SQLiteDialect) CreateTableIfNotExistsSQL(table *types.Table) string {
+	return d.createTableSQL("CREATE TABLE IF NOT EXISTS", table)
+}
+
+// createTableSQL renders a CREATE TABLE statement with the given prefix.
+// Composite primary keys and table-level foreign keys are emitted as table constraints.
+func (d *SQLiteDialect) createTableSQL(prefix string, table *types.Table) string {
+	var pkColumns []string
+	for _, col := range table.Columns {
+		if col.IsPrimaryKey {
+			pkColumns = append(pkColumns, d.QuoteIdentifier(col.Name))
+		}
+	}
+
+	var defs []string
+	for _, col := range table.Columns {
+		if len(pkColumns) > 1 && col.IsPrimaryKey {
+			c := *col
+			c.IsPrimaryKey = false
+			defs = append(defs, d.ColumnDefinitionSQL(&c))
+			continue
+		}
+		defs = append(defs, d.ColumnDefinitionSQL(col))
+	}
+	if len(pkColumns) > 1 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ", ")))
+	}
+	for _, fk := range table.ForeignKeys {
+		defs = append(defs, d.foreignKeyConstraintSQL(fk))
+	}
+
+	return fmt.Sprintf(
+		"%s %s (\n  %s\n);",
+		prefix,
+		d.QualifyTable(table.Schema, table.Name),
+		strings.Join(defs, ",\n  "),
+	)
+}
+
+// DropTableSQL generates a DROP TABLE statement.
+func (d *SQLiteDialect) DropTableSQL(schema, name string) string {
+	return fmt.Sprintf("DROP TABLE %s;", d.QualifyTable(schema, name))
+}
+
+// DropTableIfExistsSQL generates a DROP TABLE IF EXISTS statement.
+func (d *SQLiteDialect) DropTableIfExistsSQL(schema, name string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.QualifyTable(schema, name))
+}
+
+// ColumnDefinitionSQL generates the column definition SQL.
+// Auto-incrementing columns become INTEGER PRIMARY KEY AUTOINCREMENT, which is
+// the only form SQLite accepts for AUTOINCREMENT.
+func (d *SQLiteDialect) ColumnDefinitionSQL(col *types.Column) string {
+	var parts []string
+
+	parts = append(parts, d.QuoteIdentifier(col.Name))
+
+	if d.isSerial(col) && col.IsPrimaryKey {
+		parts = append(parts, "INTEGER PRIMARY KEY AUTOINCREMENT")
+	} else {
+		parts = append(parts, d.mapDataType(col))
+		if col.IsPrimaryKey {
+			parts = append(parts, "PRIMARY KEY")
+		}
+		if col.IsNotNull && !col.IsPrimaryKey {
+			parts = append(parts, "NOT NULL")
+		}
+	}
+	if col.IsUnique && !col.IsPrimaryKey {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.HasDefault {
+		parts = append(parts, fmt.Sprintf("DEFAULT %v", d.formatDefault(col.DefaultValue)))
+	}
+	if col.RefTable != "" && col.RefColumn != "" {
+		refPart := fmt.Sprintf(
+			"REFERENCES %s(%s)",
+			d.QuoteIdentifier(col.RefTable),
+			d.QuoteIdentifier(col.RefColumn),
+		)
+		if col.RefOnDelete != "" {
+			refPart += " ON DELETE " + col.RefOnDelete
+		}
+		if col.RefOnUpdate != "" {
+			refPart += " ON UPDATE " + col.RefOnUpdate
+		}
+		parts = append(parts, refPart)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// isSerial reports whether the column is an auto-incrementing integer.
+func (d *SQLiteDialect) isSerial(col *types.Column) bool {
+	return col.DataType == "serial" || col.DataType == "bigserial"
+}
+
+// mapDataType maps generic types to SQLite type names.
+// SQLite uses type affinity, so the names are chosen to land on the right
+// affinity while staying readable (and recognisable to the go-sqlite3 driver).
+func (d *SQLiteDialect) mapDataType(col *types.Column) string {
+	switch col.DataType {
+	case "varchar":
+		if col.Length > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", col.Length)
+		}
+		return "VARCHAR(255)"
+	case "char":
+		if col.Length > 0 {
+			return fmt.Sprintf("CHAR(%d)", col.Length)
+		}
+		return "CHAR(1)"
+	case "int", "serial", "bigserial":
+		return "INTEGER"
+	case "bigint":
+		return "BIGINT"
+	case "smallint":
+		return "SMALLINT"
+	case "float":
+		return "REAL"
+	case "double":
+		return "DOUBLE"
+	case "decimal":
+		p := col.Precision
+		if p == 0 {
+			p = 10
+		}
+		s := col.Scale
+		if s == 0 {
+			s = 2
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", p, s)
+	case "boolean":
+		return "BOOLEAN"
+	case "text":
+		return "TEXT"
+	case "date":
+		return "DATE"
+	case "time":
+		return "TIME"
+	case "timestamp":
+		return "TIMESTAMP"
+	case "uuid":
+		return "TEXT" // SQLite has no UUID type
+	case "json", "jsonb":
+		return "TEXT" // JSON is stored as text and queried with the json1 functions
+	case "binary":
+		return "BLOB"
+	default:
+		return strings.ToUpper(col.DataType)
+	}
+}
+
+// formatDefault formats a default value for SQL.
+func (d *SQLiteDialect) formatDefault(value any) string {
+	switch v := value.(type) {
+	case rawDefault:
+		return string(v)
+	case string:
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''"))
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// AlterTableSQL generates ALTER TABLE statements for the actions SQLite supports natively.
+// Actions that need a table rebuild cannot be rendered without the current table
+// definition, so they are emitted as SQL comments; Schema uses RebuildTableSQL
+// instead whenever a connection is available.
+func (d *SQLiteDialect) AlterTableSQL(schema, tableName string, actions []*types.TableAction) []string {
+	qualifiedTable := d.QualifyTable(schema, tableName)
+	var statements []string
+	for _, action := range actions {
+		switch action.Type {
+		case types.ActionDropColumn:
+			statements = append(statements, d.dropColumnSQL(qualifiedTable, action.Name))
+		case types.ActionAddColumn:
+			statements = append(statements, d.addColumnSQL(qualifiedTable, action.Column))
+		case types.ActionRenameColumn:
+			statements = append(statements, d.renameColumnSQL(qualifiedTable, action.Name, action.NewName))
+		case types.ActionCreateIndex:
+			statements = append(statements, d.createIndexSQL(schema, tableName, action.Index))
+		case types.ActionDropIndex:
+			statements = append(statements, d.dropIndexSQL(schema, action.Index.Name))
+		default:
+			statements = append(statements, fmt.Sprintf("-- %s on %s requires a table rebuild", action.Type, qualifiedTable))
+		}
+	}
+	return statements
+}
+
+// NeedsRebuild reports whether any action cannot be applied with SQLite's ALTER TABLE.
+func (d *SQLiteDialect) NeedsRebuild(actions []*types.TableAction) bool {
+	for _, action := range actions {
+		switch action.Type {
+		case types.ActionDropColumn, types.ActionAddColumn, types.ActionRenameColumn,
+			types.ActionCreateIndex, types.ActionDropIndex:
+			continue
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// IntrospectTable reads a table definition using PRAGMA table_info,
+// PRAGMA foreign_key_list and PRAGMA index_list.
+func (d *SQLiteDialect) IntrospectTable(q Queryer, schema, tableName string) (*types.Table, error) {
+	table := &types.Table{Name: tableName, Schema: schema}
+
+	createSQL, err := d.tableDefinition(q, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Columns
+	rows, err := q.Query(fmt.Sprintf("PRAGMA %stable_info(%s);", d.pragmaSchema(schema), d.QuoteIdentifier(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", tableName, err)
+	}
+	var pkCount int
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning column of %s: %w", tableName, err)
+		}
+		col := &types.Column{
+			Name:         name,
+			DataType:     colType,
+			IsNotNull:    notNull == 1,
+			IsPrimaryKey: pk > 0,
+		}
+		if dflt.Valid {
+			col.HasDefault = true
+			col.DefaultValue = rawDefault(dflt.String)
+		}
+		if pk > 0 {
+			pkCount++
+		}
+		table.Columns = append(table.Columns, col)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", tableName)
+	}
+
+	// A lone INTEGER primary key declared with AUTOINCREMENT round-trips as serial.
+	if pkCount == 1 && strings.Contains(strings.ToUpper(createSQL), "AUTOINCREMENT") {
+		for _, col := range table.Columns {
+			if col.IsPrimaryKey && strings.EqualFold(col.DataType, "INTEGER") {
+				col.DataType = "serial"
+			}
+		}
+	}
+
+	// Foreign keys
+	fkNames := make(map[string]string)
+	for _, m := range sqliteFKNamePattern.FindAllStringSubmatch(createSQL, -1) {
+		fkNames[m[2]] = m[1]
+	}
+	rows, err = q.Query(fmt.Sprintf("PRAGMA %sforeign_key_list(%s);", d.pragmaSchema(schema), d.QuoteIdentifier(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("reading foreign keys of %s: %w", tableName, err)
+	}
+	// A REFERENCES clause without a column refers to the parent's primary key,
+	// and the pragma reports its column as NULL
+	implicitRefs := make(map[*types.ForeignKey]int)
+	for rows.Next() {
+		var (
+			id, seq                                   int
+			refTable, from, onUpdate, onDelete, match string
+			to                                        sql.NullString
+		)
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning foreign key of %s: %w", tableName, err)
+		}
+		name := fkNames[from]
+		if name == "" {
+			name = "fk_" + tableName + "_" + from
+		}
+		fk := &types.ForeignKey{
+			Name:      name,
+			Column:    from,
+			RefTable:  refTable,
+			RefColumn: to.String,
+			OnDelete:  d.referentialAction(onDelete),
+			OnUpdate:  d.referentialAction(onUpdate),
+			TableName: tableName,
+		}
+		if !to.Valid {
+			implicitRefs[fk] = seq
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	for fk, seq := range implicitRefs {
+		pkColumns, err := d.primaryKeyColumns(q, schema, fk.RefTable)
+		if err != nil {
+			return nil, err
+		}
+		if seq >= len(pkColumns) {
+			return nil, fmt.Errorf("foreign key %s references %s, which has no primary key column %d", fk.Name, fk.RefTable, seq+1)
+		}
+		fk.RefColumn = pkColumns[seq]
+	}
+
+	// Indexes. Explicit indexes (origin "c") are recreated after the rebuild;
+	// indexes backing UNIQUE constraints (origin "u") are folded back into the
+	// column definitions.
+	type indexEntry struct {
+		name   string
+		unique bool
+		origin string
+	}
+	var entries []indexEntry
+	rows, err = q.Query(fmt.Sprintf("PRAGMA %sindex_list(%s);", d.pragmaSchema(schema), d.QuoteIdentifier(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("reading indexes of %s: %w", tableName, err)
+	}
+	for rows.Next() {
+		var (
+			seq, unique, partial int
+			name, origin         string
+		)
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning index of %s: %w", tableName, err)
+		}
+		if origin == "pk" {
+			continue
+		}
+		entries = append(entries, indexEntry{name: name, unique: unique == 1, origin: origin})
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		columns, err := d.indexColumns(q, schema, e.name)
+		if err != nil {
+			return nil, err
+		}
+		if e.origin == "u" && len(columns) == 1 {
+			for _, col := range table.Columns {
+				if col.Name == columns[0] {
+					col.IsUnique = true
+				}
+			}
+			continue
+		}
+		name := e.name
+		if e.origin == "u" {
+			name = "uq_" + tableName + "_" + strings.Join(columns, "_")
+		}
+		table.Indexes = append(table.Indexes, &types.Index{
+			Name:      name,
+			Columns:   columns,
+			IsUnique:  e.unique,
+			TableName: tableName,
+		})
+	}
+
+	return table, nil
+}
+
+// primaryKeyColumns returns the primary key columns of a table, in key order.
+func (d *SQLiteDialect) primaryKeyColumns(q Queryer, schema, tableName string) ([]string, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA %stable_info(%s);", d.pragmaSchema(schema), d.QuoteIdentifier(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	byPosition := make(map[int]string)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("scanning column of %s: %w", tableName, err)
+		}
+		if pk > 0 {
+			byPosition[pk] = name
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(byPosition))
+	for pos := 1; pos <= len(byPosition); pos++ {
+		columns = append(columns, byPosition[pos])
+	}
+	return columns, nil
+}
+
+// tableDefinition returns the original CREATE TABLE statement from sqlite_master.
+func (d *SQLiteDialect) tableDefinition(q Queryer, schema, tableName string) (string, error) {
+	master := "sqlite_master"
+	if schema != "" {
+		master = d.QuoteIdentifier(schema) + ".sqlite_master"
+	}
+	rows, err := q.Query(fmt.Sprintf("SELECT sql FROM %s WHERE type = 'table' AND name = '%s';", master, tableName))
+	if err != nil {
+		return "", fmt.Errorf("reading definition of %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var createSQL sql.NullString
+	if rows.Next() {
+		if err := rows.Scan(&createSQL); err != nil {
+			return "", fmt.Errorf("scanning definition of %s: %w", tableName, err)
+		}
+	}
+	return createSQL.String, rows.Err()
+}
+
+// indexColumns returns the column names of an index in key order.
+func (d *SQLiteDialect) indexColumns(q Queryer, schema, indexName string) ([]string, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA %sindex_info(%s);", d.pragmaSchema(schema), d.QuoteIdentifier(indexName)))
+	if err != nil {
+		return nil, fmt.Errorf("reading index %s: %w", indexName, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			seqno, cid int
+			name       sql.NullString
+		)
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, fmt.Errorf("scanning index %s: %w", indexName, err)
+		}
+		columns = append(columns, name.String)
+	}
+	return columns, rows.Err()
+}
+
+// pragmaSchema returns the schema prefix for a PRAGMA statement.
+func (d *SQLiteDialect) pragmaSchema(schema string) string {
+	if schema == "" {
+		return ""
+	}
+	return d.QuoteIdentifier(schema) + "."
+}
+
+// referentialAction normalises a PRAGMA foreign_key_list action.
+// NO ACTION is SQLite's default and is left out of generated SQL.
+func (d *SQLiteDialect) referentialAction(action string) string {
+	if strings.EqualFold(action, "NO ACTION") {
+		return ""
+	}
+	return action
+}
+
+// RebuildTableSQL generates the statements that recreate a table with the actions applied:
+// create a temporary table with the new definition, copy the surviving columns,
+// drop the original table, rename the temporary one and recreate the indexes.
+//
+// The statements run inside the migration transaction. SQLite ignores
+// PRAGMA foreign_keys inside a transaction, so databases that enforce foreign
+// keys should not have other tables referencing a table that is rebuilt.
+func (d *SQLiteDialect) RebuildTableSQL(current *types.Table, actions []*types.TableAction) []string {
+	// source maps a new column name to the original column it is copied from.
+	source := make(map[string]string)
+	var columns []*types.Column
+	for _, col := range current.Columns {
+		c := *col
+		columns = append(columns, &c)
+		source[c.Name] = c.Name
+	}
+	var foreignKeys []*types.ForeignKey
+	for _, fk := range current.ForeignKeys {
+		f := *fk
+		foreignKeys = append(foreignKeys, &f)
+	}
+	var indexes []*types.Index
+	for _, idx := range current.Indexes {
+		i := *idx
+		i.Columns = append([]string(nil), idx.Columns...)
+		indexes = append(indexes, &i)
+	}
+
+	findColumn := func(name string) *types.Column {
+		for _, col := range columns {
+			if col.Name == name {
+				return col
+			}
+		}
+		return nil
+	}
+
+	for _, action := range actions {
+		switch action.Type {
+		case types.ActionDropColumn:
+			for i, col := range columns {
+				if col.Name == action.Name {
+					columns = append(columns[:i], columns[i+1:]...)
+					break
+				}
+			}
+			delete(source, action.Name)
+			foreignKeys = filterForeignKeys(foreignKeys, func(fk *types.ForeignKey) bool {
+				return fk.Column != action.Name
+			})
+			indexes = filterIndexes(indexes, func(idx *types.Index) bool {
+				for _, c := range idx.Columns {
+					if c == action.Name {
+						return false
+					}
+				}
+				return true
+			})
+		case types.ActionAddColumn:
+			c := *action.Column
+			columns = append(columns, &c)
+		case types.ActionRenameColumn:
+			if col := findColumn(action.Name); col != nil {
+				col.Name = action.NewName
+				if from, ok := source[action.Name]; ok {
+					delete(source, action.Name)
+					source[action.NewName] = from
+				}
+			}
+			for _, fk := range foreignKeys {
+				if fk.Column == action.Name {
+					fk.Column = action.NewName
+				}
+			}
+			for _, idx := range indexes {
+				for i, c := range idx.Columns {
+					if c == action.Name {
+						idx.Columns[i] = action.NewName
+					}
+				}
+			}
+		case types.ActionChangeColumnType:
+			if col := findColumn(action.Column.Name); col != nil {
+				col.DataType = action.Column.DataType
+				col.Length = action.Column.Length
+				col.Precision = action.Column.Precision
+				col.Scale = action.Column.Scale
+				col.IsUnsigned = action.Column.IsUnsigned
+			}
+		case types.ActionSetColumnNotNull:
+			if col := findColumn(actionColumnName(action)); col != nil {
+				col.IsNotNull = true
+			}
+		case types.ActionDropColumnNotNull:
+			if col := findColumn(actionColumnName(action)); col != nil {
+				col.IsNotNull = false
+			}
+		case types.ActionSetColumnDefault:
+			if col := findColumn(action.Name); col != nil {
+				col.HasDefault = true
+				col.DefaultValue = action.DefaultValue
+			}
+		case types.ActionDropColumnDefault:
+			if col := findColumn(action.Name); col != nil {
+				col.HasDefault = false
+				col.DefaultValue = nil
+			}
+		case types.ActionCreateIndex:
+			i := *action.Index
+			indexes = append(indexes, &i)
+		case types.ActionDropIndex:
+			indexes = filterIndexes(indexes, func(idx *types.Index) bool {
+				return idx.Name != action.Index.Name
+			})
+		case types.ActionAddForeignKey:
+			f := *action.ForeignKey
+			foreignKeys = append(foreignKeys, &f)
+		case types.ActionDropForeignKey:
+			foreignKeys = filterForeignKeys(foreignKeys, func(fk *types.ForeignKey) bool {
+				return fk.Name != action.ForeignKey.Name
+			})
+			// Column-level REFERENCES have no name of their own; match the generated one.
+			for _, col := range columns {
+				if col.RefTable != "" && "fk_"+current.Name+"_"+col.Name == action.ForeignKey.Name {
+					col.RefTable, col.RefColumn = "", ""
+				}
+			}
+		case types.ActionDropPrimary:
+			for _, col := range columns {
+				if col.IsPrimaryKey && d.isSerial(col) {
+					col.DataType = "int"
+				}
+				col.IsPrimaryKey = false
+			}
+		}
+	}
+
+	tmpName := "_jone_tmp_" + current.Name
+	tmpTable := &types.Table{
+		Name:        tmpName,
+		Schema:      current.Schema,
+		Columns:     columns,
+		ForeignKeys: foreignKeys,
+	}
+
+	var targetCols, sourceCols []string
+	for _, col := range columns {
+		if from, ok := source[col.Name]; ok {
+			targetCols = append(targetCols, d.QuoteIdentifier(col.Name))
+			sourceCols = append(sourceCols, d.QuoteIdentifier(from))
+		}
+	}
+
+	statements := []string{d.CreateTableSQL(tmpTable)}
+	if len(targetCols) > 0 {
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;",
+			d.QualifyTable(current.Schema, tmpName),
+			strings.Join(targetCols, ", "),
+			strings.Join(sourceCols, ", "),
+			d.QualifyTable(current.Schema, current.Name)))
+	}
+	statements = append(statements,
+		d.DropTableSQL(current.Schema, current.Name),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+			d.QualifyTable(current.Schema, tmpName),
+			d.QuoteIdentifier(current.Name)),
+	)
+	for _, idx := range indexes {
+		statements = append(statements, d.createIndexSQL(current.Schema, current.Name, idx))
+	}
+	return statements
+}
+
+// actionColumnName returns the column targeted by a nullability action.
+func actionColumnName(action *types.TableAction) string {
+	if action.Name == "" && action.Column != nil {
+		return action.Column.Name
+	}
+	return action.Name
+}
+
+// filterForeignKeys returns the foreign keys for which keep returns true.
+func filterForeignKeys(fks []*types.ForeignKey, keep func(*types.ForeignKey) bool) []*types.ForeignKey {
+	var out []*types.ForeignKey
+	for _, fk := range fks {
+		if keep(fk) {
+			out = append(out, fk)
+		}
+	}
+	return out
+}
+
+// filterIndexes returns the indexes for which keep returns true.
+func filterIndexes(indexes []*types.Index, keep func(*types.Index) bool) []*types.Index {
+	var out []*types.Index
+	for _, idx := range indexes {
+		if keep(idx) {
+			out = append(out, idx)
+		}
+	}
+	return out
+}
+
+// foreignKeyConstraintSQL generates a table-level FOREIGN KEY constraint.
+func (d *SQLiteDialect) foreignKeyConstraintSQL(fk *types.ForeignKey) string {
+	sql := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(%s)",
+		d.QuoteIdentifier(fk.Name),
+		d.QuoteIdentifier(fk.Column),
+		d.QuoteIdentifier(fk.RefTable),
+		d.QuoteIdentifier(fk.RefColumn))
+
+	if fk.OnDelete != "" {
+		sql += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		sql += " ON UPDATE " + fk.OnUpdate
+	}
+	return sql
+}
+
+// createIndexSQL generates a CREATE INDEX statement.
+// In SQLite the schema qualifies the index name; the table name must be unqualified.
+func (d *SQLiteDialect) createIndexSQL(schema, tableName string, idx *types.Index) string {
+	unique := ""
+	if idx.IsUnique {
+		unique = "UNIQUE "
+	}
+
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		cols[i] = d.QuoteIdentifier(c)
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);",
+		unique,
+		d.QualifyTable(schema, idx.Name),
+		d.QuoteIdentifier(tableName),
+		strings.Join(cols, ", "))
+}
+
+// dropIndexSQL generates a DROP INDEX statement.
+func (d *SQLiteDialect) dropIndexSQL(schema, name string) string {
+	return fmt.Sprintf("DROP INDEX %s;", d.QualifyTable(schema, name))
+}
+
+// dropColumnSQL generates an ALTER TABLE DROP COLUMN statement (SQLite 3.35+).
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *SQLiteDialect) dropColumnSQL(tableName, columnName string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;",
+		tableName,
+		d.QuoteIdentifier(columnName))
+}
+
+// addColumnSQL generates an ALTER TABLE ADD COLUMN statement.
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *SQLiteDialect) addColumnSQL(tableName string, column *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;",
+		tableName,
+		d.ColumnDefinitionSQL(column))
+}
+
+// renameColumnSQL generates an ALTER TABLE RENAME COLUMN statement (SQLite 3.25+).
+// tableName should be pre-qualified (e.g., from QualifyTable).
+func (d *SQLiteDialect) renameColumnSQL(tableName, oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;",
+		tableName,
+		d.QuoteIdentifier(oldName),
+		d.QuoteIdentifier(newName))
+}
+
+// HasTableSQL returns SQL to check if a table exists in SQLite.
+func (d *SQLiteDialect) HasTableSQL(schema, tableName string) string {
+	if schema != "" {
+		return fmt.Sprintf(`SELECT COUNT(*) FROM %s.sqlite_master WHERE type = 'table' AND name = '%s'`, d.QuoteIdentifier(schema), tableName)
+	}
+	return fmt.Sprintf(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = '%s'`, tableName)
+}
+
+// HasColumnSQL returns SQL to check if a column exists in SQLite.
+func (d *SQLiteDialect) HasColumnSQL(schema, tableName, columnName string) string {
+	if schema != "" {
+		return fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s', '%s') WHERE name = '%s'`, tableName, schema, columnName)
+	}
+	return fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = '%s'`, tableName, columnName)
+}
+
+// CommentColumnSQL returns an empty string: SQLite does not support column comments.
+func (d *SQLiteDialect) CommentColumnSQL(tableName, columnName, comment string) string {
+	return ""
+}
+
+// QualifyTable returns a schema-qualified table name.
+// In SQLite the schema is the name of an attached database (e.g. "main").
+func (d *SQLiteDialect) QualifyTable(schema, tableName string) string {
+	if schema == "" {
+		return d.QuoteIdentifier(tableName)
+	}
+	return fmt.Sprintf("%s.%s", d.QuoteIdentifier(schema), d.QuoteIdentifier(tableName))
+}
+
+// --- Migration Tracking Methods ---
+
+// CreateMigrationsTableSQL returns SQL to create the migrations tracking table.
+func (d *SQLiteDialect) CreateMigrationsTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name VARCHAR(255) NOT NULL UNIQUE,
+	batch INTEGER NOT NULL,
+	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	checksum VARCHAR(64),
+	duration_ms INTEGER,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32)
+);`, d.QuoteIdentifier(tableName))
+}
+
+// RecordMigrationSQL returns parameterized SQL to record a migration and how it was applied.
+func (d *SQLiteDialect) RecordMigrationSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (?, ?, ?, ?, ?, ?, ?);",
+		d.QuoteIdentifier(tableName))
+}
+
+// DeleteMigrationSQL returns parameterized SQL to remove a migration record.
+func (d *SQLiteDialect) DeleteMigrationSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE name = ?;",
+		d.QuoteIdentifier(tableName))
+}
+
+// CreateMigrationLogTableSQL returns SQL to create the migration history table.
+func (d *SQLiteDialect) CreateMigrationLogTableSQL(logTable string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name VARCHAR(255) NOT NULL,
+	direction VARCHAR(4) NOT NULL,
+	batch INTEGER,
+	status VARCHAR(16) NOT NULL,
+	duration_ms INTEGER,
+	applied_by VARCHAR(255),
+	hostname VARCHAR(255),
+	jone_version VARCHAR(32),
+	error TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`, d.QuoteIdentifier(logTable))
+}
+
+// InsertMigrationLogSQL returns parameterized SQL to append a history entry.
+func (d *SQLiteDialect) InsertMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);",
+		d.QuoteIdentifier(logTable))
+}
+
+// GetMigrationLogSQL returns SQL to get every history entry ordered by id.
+func (d *SQLiteDialect) GetMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(logTable))
+}
+
+// sqliteScripts describes the SQLite script syntax for SQL file migrations.
+var sqliteScripts = splitStyle{triggerBodies: true}
+
+// sqliteLiterals describes SQLite bind parameters and literals for migrate:sql.
+var sqliteLiterals = literalStyle{
+	placeholder: placeholderQuestion,
+	boolsAsInts: true,
+	bytes:       hexBytes,
+}
+
+// SupportsTransactionalDDL reports whether DDL can be rolled back.
+// SQLite rolls back DDL with the rest of a transaction.
+func (d *SQLiteDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// BeginTransactionSQL returns the statement that starts a transaction.
+func (d *SQLiteDialect) BeginTransactionSQL() string {
+	return "BEGIN;"
+}
+
+// CommitTransactionSQL returns the statement that commits a transaction.
+func (d *SQLiteDialect) CommitTransactionSQL() string {
+	return "COMMIT;"
+}
+
+// InsertMigrationScriptSQL returns parameterized SQL that records a migration
+// in the batch after the current last one plus an offset.
+func (d *SQLiteDialect) InsertMigrationScriptSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum) SELECT ?, COALESCE(MAX(batch), 0) + ?, ? FROM %s;",
+		d.QuoteIdentifier(tableName), d.QuoteIdentifier(tableName))
+}
+
+// SplitStatements returns the statements in script. A CREATE TRIGGER
+// statement runs until the END of its body.
+func (d *SQLiteDialect) SplitStatements(script string) []string {
+	return sqliteScripts.split(script)
+}
+
+// InlineArgs returns query with its placeholders replaced by args as SQLite literals.
+func (d *SQLiteDialect) InlineArgs(query string, args []any) (string, error) {
+	return sqliteLiterals.inline(query, args)
+}
+
+// TerminateStatement returns stmt ended with ";".
+func (d *SQLiteDialect) TerminateStatement(stmt string) string {
+	return sqliteScripts.terminate(stmt)
+}
+
+// GetAppliedMigrationsSQL returns SQL to get all applied migration names ordered by id.
+func (d *SQLiteDialect) GetAppliedMigrationsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name FROM %s ORDER BY id;",
+		d.QuoteIdentifier(tableName))
+}
+
+// AddMigrationsColumnSQL returns SQL to add a column to the migrations tracking table.
+func (d *SQLiteDialect) AddMigrationsColumnSQL(tableName string, col *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;",
+		d.QuoteIdentifier(tableName), d.ColumnDefinitionSQL(col))
+}
+
+// UpdateMigrationChecksumSQL returns parameterized SQL to replace a migration's checksum.
+func (d *SQLiteDialect) UpdateMigrationChecksumSQL(tableName string) string {
+	return fmt.Sprintf("UPDATE %s SET checksum = ? WHERE name = ?;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationRecordsSQL returns SQL to get name, batch, checksum and applied_at of applied migrations ordered by id.
+func (d *SQLiteDialect) GetMigrationRecordsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name, batch, checksum, applied_at FROM %s ORDER BY id;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetLastBatchSQL returns SQL to get the highest batch number.
+func (d *SQLiteDialect) GetLastBatchSQL(tableName string) string {
+	return fmt.Sprintf("SELECT COALESCE(MAX(batch), 0) FROM %s;",
+		d.QuoteIdentifier(tableName))
+}
+
+// GetMigrationsByBatchSQL returns parameterized SQL to get migrations for a batch.
+func (d *SQLiteDialect) GetMigrationsByBatchSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name FROM %s WHERE batch = ? ORDER BY id DESC;",
+		d.QuoteIdentifier(tableName))
+}
+
+// --- Introspection Methods ---
+
+// DropAllObjectsSQL returns statements that drop every view and table in the
+// database (schema = attached database name). Foreign key enforcement is turned
+// off while dropping if it is on for the connection.
+func (d *SQLiteDialect) DropAllObjectsSQL(q Queryer, schema string, keep []string) ([]string, error) {
+	master := "sqlite_master"
+	if schema != "" {
+		master = d.QuoteIdentifier(schema) + ".sqlite_master"
+	}
+
+	rows, err := queryNames(q, keep,
+		"SELECT name, type FROM "+master+" WHERE type IN ('view', 'table') AND name NOT LIKE 'sqlite_%' ORDER BY type DESC, name")
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	fkRows, err := queryNames(q, nil, "PRAGMA foreign_keys;")
+	if err != nil {
+		return nil, fmt.Errorf("reading foreign_keys pragma: %w", err)
+	}
+	foreignKeys := len(fkRows) == 1 && fkRows[0][0] == "1"
+
+	var stmts []string
+	if foreignKeys {
+		stmts = append(stmts, "PRAGMA foreign_keys = OFF;")
+	}
+	for _, row := range rows {
+		stmts = append(stmts, fmt.Sprintf("DROP %s IF EXISTS %s;", strings.ToUpper(row[1]), d.QualifyTable(schema, row[0])))
+	}
+	if foreignKeys {
+		stmts = append(stmts, "PRAGMA foreign_keys = ON;")
+	}
+	return stmts, nil
+}
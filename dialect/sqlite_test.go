@@ -0,0 +1,269 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/types"
+)
+
+func TestSQLiteDialect_Name(t *testing.T) {
+	d := &SQLiteDialect{}
+	if got := d.Name(); got != "sqlite" {
+		t.Errorf("Name() = %q, want %q", got, "sqlite")
+	}
+}
+
+func TestSQLiteDialect_DriverName(t *testing.T) {
+	d := &SQLiteDialect{}
+	if got := d.DriverName(); got != "sqlite3" {
+		t.Errorf("DriverName() = %q, want %q", got, "sqlite3")
+	}
+}
+
+func TestSQLiteDialect_FormatDSN(t *testing.T) {
+	d := &SQLiteDialect{}
+
+	tests := []struct {
+		name string
+		conn config.Connection
+		want string
+	}{
+		{
+			name: "file path",
+			conn: config.Connection{Database: "./jone.db"},
+			want: "./jone.db",
+		},
+		{
+			name: "uri with options",
+			conn: config.Connection{Database: "file:test.db?cache=shared"},
+			want: "file:test.db?cache=shared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.FormatDSN(tt.conn); got != tt.want {
+				t.Errorf("FormatDSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteDialect_CreateTableSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+	table := &types.Table{
+		Name: "users",
+		Columns: []*types.Column{
+			{Name: "id", DataType: "serial", IsPrimaryKey: true, IsNotNull: true},
+			{Name: "email", DataType: "varchar", Length: 100, IsNotNull: true, IsUnique: true},
+			{Name: "active", DataType: "boolean", HasDefault: true, DefaultValue: true},
+			{Name: "data", DataType: "jsonb"},
+		},
+	}
+
+	got := d.CreateTableSQL(table)
+	want := `CREATE TABLE "users" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "email" VARCHAR(100) NOT NULL UNIQUE,
+  "active" BOOLEAN DEFAULT 1,
+  "data" TEXT
+);`
+	if got != want {
+		t.Errorf("CreateTableSQL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSQLiteDialect_CreateTableCompositePrimaryKey(t *testing.T) {
+	d := &SQLiteDialect{}
+	table := &types.Table{
+		Name: "memberships",
+		Columns: []*types.Column{
+			{Name: "user_id", DataType: "int", IsPrimaryKey: true},
+			{Name: "team_id", DataType: "int", IsPrimaryKey: true},
+		},
+	}
+
+	got := d.CreateTableSQL(table)
+	if !strings.Contains(got, `PRIMARY KEY ("user_id", "team_id")`) {
+		t.Errorf("composite primary key missing, got: %s", got)
+	}
+	if strings.Contains(got, `"user_id" INTEGER PRIMARY KEY`) {
+		t.Errorf("column-level primary key emitted for composite key, got: %s", got)
+	}
+}
+
+func TestSQLiteDialect_AlterTableSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+	actions := []*types.TableAction{
+		{Type: types.ActionAddColumn, Column: &types.Column{Name: "phone", DataType: "varchar", Length: 20}},
+		{Type: types.ActionRenameColumn, Name: "name", NewName: "full_name"},
+		{Type: types.ActionDropColumn, Name: "legacy"},
+		{Type: types.ActionCreateIndex, Index: &types.Index{Name: "idx_users_phone", Columns: []string{"phone"}}},
+		{Type: types.ActionDropIndex, Index: &types.Index{Name: "idx_users_old"}},
+	}
+
+	got := d.AlterTableSQL("", "users", actions)
+	want := []string{
+		`ALTER TABLE "users" ADD COLUMN "phone" VARCHAR(20);`,
+		`ALTER TABLE "users" RENAME COLUMN "name" TO "full_name";`,
+		`ALTER TABLE "users" DROP COLUMN "legacy";`,
+		`CREATE INDEX "idx_users_phone" ON "users" ("phone");`,
+		`DROP INDEX "idx_users_old";`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AlterTableSQL() returned %d statements, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSQLiteDialect_NeedsRebuild(t *testing.T) {
+	d := &SQLiteDialect{}
+
+	tests := []struct {
+		name    string
+		actions []*types.TableAction
+		want    bool
+	}{
+		{"add column", []*types.TableAction{{Type: types.ActionAddColumn}}, false},
+		{"rename column", []*types.TableAction{{Type: types.ActionRenameColumn}}, false},
+		{"create index", []*types.TableAction{{Type: types.ActionCreateIndex}}, false},
+		{"change type", []*types.TableAction{{Type: types.ActionChangeColumnType}}, true},
+		{"drop foreign key", []*types.TableAction{{Type: types.ActionDropForeignKey}}, true},
+		{"mixed", []*types.TableAction{{Type: types.ActionAddColumn}, {Type: types.ActionSetColumnDefault}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.NeedsRebuild(tt.actions); got != tt.want {
+				t.Errorf("NeedsRebuild() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteDialect_RebuildTableSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+	current := &types.Table{
+		Name: "posts",
+		Columns: []*types.Column{
+			{Name: "id", DataType: "serial", IsPrimaryKey: true, IsNotNull: true},
+			{Name: "user_id", DataType: "INTEGER", IsNotNull: true},
+			{Name: "title", DataType: "VARCHAR(255)"},
+			{Name: "status", DataType: "VARCHAR(20)", HasDefault: true, DefaultValue: rawDefault("'draft'")},
+		},
+		ForeignKeys: []*types.ForeignKey{
+			{Name: "fk_posts_user_id", Column: "user_id", RefTable: "users", RefColumn: "id", OnDelete: "CASCADE"},
+		},
+		Indexes: []*types.Index{
+			{Name: "idx_posts_title", Columns: []string{"title"}},
+		},
+	}
+	actions := []*types.TableAction{
+		{Type: types.ActionDropForeignKey, ForeignKey: &types.ForeignKey{Name: "fk_posts_user_id"}},
+		{Type: types.ActionRenameColumn, Name: "title", NewName: "headline"},
+		{Type: types.ActionChangeColumnType, Column: &types.Column{Name: "status", DataType: "text"}},
+		{Type: types.ActionSetColumnNotNull, Name: "headline"},
+		{Type: types.ActionAddColumn, Column: &types.Column{Name: "views", DataType: "int", HasDefault: true, DefaultValue: 0}},
+	}
+
+	got := d.RebuildTableSQL(current, actions)
+	want := []string{
+		`CREATE TABLE "_jone_tmp_posts" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER NOT NULL,
+  "headline" VARCHAR(255) NOT NULL,
+  "status" TEXT DEFAULT 'draft',
+  "views" INTEGER DEFAULT 0
+);`,
+		`INSERT INTO "_jone_tmp_posts" ("id", "user_id", "headline", "status") SELECT "id", "user_id", "title", "status" FROM "posts";`,
+		`DROP TABLE "posts";`,
+		`ALTER TABLE "_jone_tmp_posts" RENAME TO "posts";`,
+		`CREATE INDEX "idx_posts_title" ON "posts" ("headline");`,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("RebuildTableSQL() returned %d statements, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statement %d =\n%s\nwant\n%s", i, got[i], want[i])
+		}
+	}
+
+	// The input table must not be modified.
+	if current.Columns[2].Name != "title" || len(current.ForeignKeys) != 1 {
+		t.Error("RebuildTableSQL modified the current table definition")
+	}
+}
+
+func TestSQLiteDialect_RebuildTableSQL_AddForeignKey(t *testing.T) {
+	d := &SQLiteDialect{}
+	current := &types.Table{
+		Name:   "posts",
+		Schema: "main",
+		Columns: []*types.Column{
+			{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+			{Name: "user_id", DataType: "INTEGER"},
+		},
+	}
+	actions := []*types.TableAction{
+		{Type: types.ActionAddForeignKey, ForeignKey: &types.ForeignKey{
+			Name: "fk_posts_user_id", Column: "user_id", RefTable: "users", RefColumn: "id", OnDelete: "SET NULL",
+		}},
+	}
+
+	got := d.RebuildTableSQL(current, actions)
+	if !strings.Contains(got[0], `CONSTRAINT "fk_posts_user_id" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE SET NULL`) {
+		t.Errorf("foreign key constraint missing, got: %s", got[0])
+	}
+	if !strings.HasPrefix(got[0], `CREATE TABLE "main"."_jone_tmp_posts"`) {
+		t.Errorf("temporary table not schema-qualified, got: %s", got[0])
+	}
+	if got[3] != `ALTER TABLE "main"."_jone_tmp_posts" RENAME TO "posts";` {
+		t.Errorf("rename statement = %q", got[3])
+	}
+}
+
+func TestSQLiteDialect_CreateMigrationsTableSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+
+	sql := d.CreateMigrationsTableSQL("jone_migrations")
+
+	if !strings.Contains(sql, `CREATE TABLE IF NOT EXISTS "jone_migrations"`) {
+		t.Errorf("CREATE TABLE IF NOT EXISTS missing, got: %s", sql)
+	}
+	if !strings.Contains(sql, "INTEGER PRIMARY KEY AUTOINCREMENT") {
+		t.Errorf("autoincrement id missing, got: %s", sql)
+	}
+}
+
+func TestSQLiteDialect_RecordMigrationSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+
+	got := d.RecordMigrationSQL("jone_migrations")
+	want := `INSERT INTO "jone_migrations" (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+	if got != want {
+		t.Errorf("RecordMigrationSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestSQLiteDialect_CommentColumnSQL(t *testing.T) {
+	d := &SQLiteDialect{}
+	if got := d.CommentColumnSQL(`"users"`, "email", "address"); got != "" {
+		t.Errorf("CommentColumnSQL() = %q, want empty", got)
+	}
+}
+
+func TestSQLiteDialect_UsesLockTable(t *testing.T) {
+	var d Dialect = &SQLiteDialect{}
+	if _, ok := d.(Locker); ok {
+		t.Error("SQLiteDialect should not implement Locker; runs use the lock table")
+	}
+}
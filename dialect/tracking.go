@@ -0,0 +1,122 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Grandbusta/jone/types"
+)
+
+// TrackingSQL generates the SQL for the parts of migration tracking added
+// after the Dialect interface was first released: the columns recording how
+// each migration was applied, checksums, and the append-only history table.
+// The built-in dialects implement it; Tracking falls back to portable SQL for
+// dialects that don't.
+type TrackingSQL interface {
+	// RecordMigrationSQL returns parameterized SQL to record a migration.
+	// Parameters: $1=name, $2=batch, $3=checksum, $4=duration_ms,
+	// $5=applied_by, $6=hostname, $7=jone_version
+	RecordMigrationSQL(tableName string) string
+
+	// AddMigrationsColumnSQL returns SQL to add a column to an existing
+	// migrations tracking table created by an earlier version of jone.
+	AddMigrationsColumnSQL(tableName string, col *types.Column) string
+
+	// UpdateMigrationChecksumSQL returns parameterized SQL to replace a migration's checksum.
+	// Parameters: $1=checksum, $2=name
+	UpdateMigrationChecksumSQL(tableName string) string
+
+	// GetMigrationRecordsSQL returns SQL to get name, batch, checksum and
+	// applied_at of all applied migrations ordered by id.
+	GetMigrationRecordsSQL(tableName string) string
+
+	// CreateMigrationLogTableSQL returns SQL to create the append-only history
+	// table that records every up and down run, if it doesn't exist.
+	CreateMigrationLogTableSQL(logTable string) string
+
+	// InsertMigrationLogSQL returns parameterized SQL to append a history entry.
+	// Parameters: $1=name, $2=direction, $3=batch, $4=status, $5=duration_ms,
+	// $6=applied_by, $7=hostname, $8=jone_version, $9=error
+	InsertMigrationLogSQL(logTable string) string
+
+	// GetMigrationLogSQL returns SQL to get name, direction, batch, status,
+	// duration_ms, applied_by, hostname, jone_version, error and created_at of
+	// every history entry ordered by id.
+	GetMigrationLogSQL(logTable string) string
+}
+
+// Tracking returns the tracking SQL for d: d itself if it implements
+// TrackingSQL, or SQL built from its QuoteIdentifier, QualifyTable,
+// CreateTableIfNotExistsSQL and bind parameters.
+func Tracking(d Dialect) TrackingSQL {
+	if t, ok := d.(TrackingSQL); ok {
+		return t
+	}
+	return portableTracking{d}
+}
+
+// portableTracking implements TrackingSQL with SQL that only depends on the
+// required Dialect methods and standard SQL.
+type portableTracking struct {
+	d Dialect
+}
+
+// params returns the bind parameters for n arguments, separated by commas.
+func (p portableTracking) params(n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = Placeholder(p.d, i+1)
+	}
+	return strings.Join(params, ", ")
+}
+
+func (p portableTracking) RecordMigrationSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (%s);",
+		p.d.QualifyTable("", tableName), p.params(7))
+}
+
+func (p portableTracking) AddMigrationsColumnSQL(tableName string, col *types.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;",
+		p.d.QualifyTable("", tableName), p.d.ColumnDefinitionSQL(col))
+}
+
+func (p portableTracking) UpdateMigrationChecksumSQL(tableName string) string {
+	return fmt.Sprintf("UPDATE %s SET checksum = %s WHERE name = %s;",
+		p.d.QualifyTable("", tableName), Placeholder(p.d, 1), Placeholder(p.d, 2))
+}
+
+func (p portableTracking) GetMigrationRecordsSQL(tableName string) string {
+	return fmt.Sprintf("SELECT name, batch, checksum, applied_at FROM %s ORDER BY id;",
+		p.d.QualifyTable("", tableName))
+}
+
+// CreateMigrationLogTableSQL leaves created_at without a default, since
+// defaults aren't portable; InsertMigrationLogSQL sets it instead.
+func (p portableTracking) CreateMigrationLogTableSQL(logTable string) string {
+	return p.d.CreateTableIfNotExistsSQL(&types.Table{
+		Name: logTable,
+		Columns: []*types.Column{
+			{Name: "id", DataType: "serial", IsPrimaryKey: true, IsNotNull: true},
+			{Name: "name", DataType: "varchar", Length: 255, IsNotNull: true},
+			{Name: "direction", DataType: "varchar", Length: 4, IsNotNull: true},
+			{Name: "batch", DataType: "int"},
+			{Name: "status", DataType: "varchar", Length: 16, IsNotNull: true},
+			{Name: "duration_ms", DataType: "int"},
+			{Name: "applied_by", DataType: "varchar", Length: 255},
+			{Name: "hostname", DataType: "varchar", Length: 255},
+			{Name: "jone_version", DataType: "varchar", Length: 32},
+			{Name: "error", DataType: "text"},
+			{Name: "created_at", DataType: "timestamp"},
+		},
+	})
+}
+
+func (p portableTracking) InsertMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("INSERT INTO %s (name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at) VALUES (%s, CURRENT_TIMESTAMP);",
+		p.d.QualifyTable("", logTable), p.params(9))
+}
+
+func (p portableTracking) GetMigrationLogSQL(logTable string) string {
+	return fmt.Sprintf("SELECT name, direction, batch, status, duration_ms, applied_by, hostname, jone_version, error, created_at FROM %s ORDER BY id;",
+		p.d.QualifyTable("", logTable))
+}
@@ -0,0 +1,69 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+)
+
+// plainDialect hides every optional interface of the dialect it wraps.
+type plainDialect struct {
+	Dialect
+}
+
+func TestTracking_BuiltInDialects(t *testing.T) {
+	for _, name := range []string{"postgresql", "mysql", "sqlite3", "mssql"} {
+		d, err := GetDialect(name)
+		if err != nil {
+			t.Fatalf("GetDialect(%q) error: %v", name, err)
+		}
+		if _, ok := Tracking(d).(portableTracking); ok {
+			t.Errorf("%s: Tracking() fell back to portable SQL", name)
+		}
+	}
+}
+
+func TestTracking_Portable(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Dialect
+		want string
+	}{
+		{"question marks", plainDialect{&SQLiteDialect{}}, `INSERT INTO "jone_migrations" (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES (?, ?, ?, ?, ?, ?, ?);`},
+		{"placeholders", struct {
+			Dialect
+			Placeholders
+		}{&PostgresDialect{}, &PostgresDialect{}}, `INSERT INTO "jone_migrations" (name, batch, checksum, duration_ms, applied_by, hostname, jone_version) VALUES ($1, $2, $3, $4, $5, $6, $7);`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracking := Tracking(tt.d)
+			if _, ok := tracking.(portableTracking); !ok {
+				t.Fatalf("Tracking() = %T, want portable SQL", tracking)
+			}
+			if got := tracking.RecordMigrationSQL("jone_migrations"); got != tt.want {
+				t.Errorf("RecordMigrationSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	tracking := Tracking(plainDialect{&SQLiteDialect{}})
+	if got, want := tracking.UpdateMigrationChecksumSQL("jone_migrations"), `UPDATE "jone_migrations" SET checksum = ? WHERE name = ?;`; got != want {
+		t.Errorf("UpdateMigrationChecksumSQL() = %q, want %q", got, want)
+	}
+	create := tracking.CreateMigrationLogTableSQL("jone_migrations_log")
+	if !strings.HasPrefix(create, `CREATE TABLE IF NOT EXISTS "jone_migrations_log"`) || !strings.Contains(create, `"created_at"`) {
+		t.Errorf("CreateMigrationLogTableSQL() = %s", create)
+	}
+	if insert := tracking.InsertMigrationLogSQL("jone_migrations_log"); !strings.HasSuffix(insert, "?, CURRENT_TIMESTAMP);") {
+		t.Errorf("InsertMigrationLogSQL() = %s", insert)
+	}
+}
+
+func TestRenameTableSQL(t *testing.T) {
+	if got, want := RenameTableSQL(plainDialect{&MSSQLDialect{}}, "", "users", "members"), "ALTER TABLE [users] RENAME TO [members];"; got != want {
+		t.Errorf("RenameTableSQL() = %q, want %q", got, want)
+	}
+	if got, want := RenameTableSQL(&MSSQLDialect{}, "", "users", "members"), "EXEC sp_rename N'[users]', N'members';"; got != want {
+		t.Errorf("RenameTableSQL() = %q, want %q", got, want)
+	}
+}
@@ -2,7 +2,10 @@ module github.com/Grandbusta/jone
 
 go 1.21.3
 
-require github.com/spf13/cobra v1.10.1
+require (
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/spf13/cobra v1.10.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
@@ -0,0 +1,7 @@
+// Package version holds the jone release version, shared by the CLI and the
+// migration history it records.
+package version
+
+// Version is the current version of jone.
+// Update this before each release.
+var Version = "v0.2.0"
@@ -28,25 +28,48 @@ type Migrations = config.Migrations
 type Schema = schema.Schema
 type Table = schema.Table
 type Column = schema.Column
+type StatementError = schema.StatementError
+type Recorder = schema.Recorder
+type Statement = schema.Statement
 
 // Core types (re-exported from types package)
 type CoreTable = types.Table
 type CoreColumn = types.Column
 
 // NewSchema creates a new Schema with the given config.
+// It returns an error if the config's Client is not a registered dialect.
 var NewSchema = schema.New
 
 // Migration types (re-exported from migration package)
 type Registration = migration.Registration
+type MigrationOptions = migration.MigrationOptions
 type RunParams = migration.RunParams
 type RunOptions = migration.RunOptions
+type RunPlan = migration.RunPlan
+type HistoryEntry = migration.HistoryEntry
+type Event = migration.Event
+type ListReport = migration.ListReport
+type ListEntry = migration.ListEntry
+type Migrator = migration.Migrator
+type MigratorOption = migration.MigratorOption
+type MigrationResult = migration.Result
+type MigrationError = migration.MigrationError
 
 // RunLatest executes pending Up migrations in order.
 var RunLatest = migration.RunLatest
 
+// Plan returns the SQL RunLatest would execute, without running it.
+var Plan = migration.Plan
+
 // RunList displays all migrations with their status.
 var RunList = migration.RunList
 
+// RunHistory prints the log of every migration run.
+var RunHistory = migration.RunHistory
+
+// RunValidate checks that applied migrations have not been edited since they ran.
+var RunValidate = migration.RunValidate
+
 // RunUp runs the next pending migration or a specific one.
 var RunUp = migration.RunUp
 
@@ -56,8 +79,47 @@ var RunDown = migration.RunDown
 // RunRollback rolls back the last batch of migrations.
 var RunRollback = migration.RunRollback
 
+// RunTo migrates or rolls back the database to a specific migration.
+var RunTo = migration.RunTo
+
+// RunReset rolls back every applied migration.
+var RunReset = migration.RunReset
+
+// RunRefresh rolls back every applied migration and runs them all again.
+var RunRefresh = migration.RunRefresh
+
+// RunBaseline marks every migration up to a given one as applied without running them.
+var RunBaseline = migration.RunBaseline
+
+// RunFake marks a single migration as applied, or not applied, without running it.
+var RunFake = migration.RunFake
+
+// RunFresh drops every object in the schema and runs all migrations.
+var RunFresh = migration.RunFresh
+
+// RunSQL writes migrations as a standalone SQL script.
+var RunSQL = migration.RunSQL
+
+// SQLFile returns an Up or Down function that runs a SQL script from an fs.FS.
+var SQLFile = migration.SQLFile
+
+// NewMigrator creates a Migrator for running migrations from an application.
+var NewMigrator = migration.NewMigrator
+
+// WithLogger sets the slog.Logger a Migrator logs to.
+var WithLogger = migration.WithLogger
+
+// WithAllowMissing lets a Migrator run when applied migrations are missing from the registry.
+var WithAllowMissing = migration.WithAllowMissing
+
 // Dialect types and functions (re-exported from dialect package)
 type Dialect = dialect.Dialect
 
-// GetDialect returns a dialect implementation by name.
+// GetDialect returns a dialect implementation by name or alias.
 var GetDialect = dialect.GetDialect
+
+// RegisterDialect makes a custom dialect available under a Config.Client name.
+var RegisterDialect = dialect.Register
+
+// RegisterDialectAlias registers an alternative name for a registered dialect.
+var RegisterDialectAlias = dialect.RegisterAlias
@@ -0,0 +1,193 @@
+package migration
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// selectBaseline returns the registered migrations up to and including target
+// that are not yet applied, in order.
+func selectBaseline(target string, applied []string, regs []Registration) ([]Registration, error) {
+	targetIdx := slices.IndexFunc(regs, func(r Registration) bool { return r.Name == target })
+	if targetIdx < 0 {
+		return nil, fmt.Errorf("migration %s not found in registry", target)
+	}
+
+	var marked []Registration
+	for _, reg := range regs[:targetIdx+1] {
+		if !slices.Contains(applied, reg.Name) {
+			marked = append(marked, reg)
+		}
+	}
+	return marked, nil
+}
+
+// RunBaseline marks every migration up to and including the one named in
+// Args[0] as applied, in a new batch, without running Up. It is for adopting
+// jone on a database whose schema already exists. Migrations already applied
+// are left alone, so it can be run again after a failure.
+func RunBaseline(p RunParams) error {
+	if len(p.Options.Args) == 0 {
+		return fmt.Errorf("baseline migration name is required")
+	}
+	target := p.Options.Args[0]
+
+	if p.Options.DryRun {
+		records, err := p.dryRunRecords()
+		if err != nil {
+			return err
+		}
+		marked, err := selectBaseline(target, appliedNames(records), p.Registrations)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+fmt.Sprintf(" Would mark %d migration(s) as applied without running them:", len(marked)))
+		for _, reg := range marked {
+			fmt.Fprintf(p.stdout(), "  %s\n", reg.Name)
+		}
+		return nil
+	}
+
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+
+	applied, err := tracker.GetApplied()
+	if err != nil {
+		return err
+	}
+	marked, err := selectBaseline(target, applied, p.Registrations)
+	if err != nil {
+		return err
+	}
+	if len(marked) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Already baselined at %s", target)))
+		return nil
+	}
+
+	lastBatch, err := tracker.GetLastBatch()
+	if err != nil {
+		return err
+	}
+	batch := lastBatch + 1
+
+	for _, reg := range marked {
+		if err := tracker.RecordMigration(reg.Name, batch, checksumOrEmpty(reg, p.Schema), 0); err != nil {
+			return err
+		}
+		if err := tracker.logMarked(reg.Name, directionUp, batch, statusBaseline); err != nil {
+			return err
+		}
+		p.emit(EventMigrated, reg.Name, directionUp, batch, 0, nil)
+		fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Marked as applied: %s", reg.Name)))
+	}
+
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ Baselined %d migration(s) in batch %d", len(marked), batch)))
+	return nil
+}
+
+// checkFake returns an error if migration name can't be faked: marked as
+// applied when it isn't registered or is already applied, or, with down, marked
+// as not applied when it isn't applied. Registration isn't needed for down, so
+// records of deleted migrations can be removed too.
+func checkFake(name string, applied []string, regs map[string]Registration, down bool) error {
+	if down {
+		if !slices.Contains(applied, name) {
+			return fmt.Errorf("migration %s not found in applied migrations", name)
+		}
+		return nil
+	}
+	if _, ok := regs[name]; !ok {
+		return fmt.Errorf("migration %s not found in registry", name)
+	}
+	if slices.Contains(applied, name) {
+		return fmt.Errorf("migration %s is already applied", name)
+	}
+	return nil
+}
+
+// RunFake records the migration named in Args[0] as applied without running
+// Up, in a new batch. With Options.Down, it removes the record instead,
+// without running Down. Either way the change is added to the history log.
+func RunFake(p RunParams) error {
+	if len(p.Options.Args) == 0 {
+		return fmt.Errorf("migration name is required")
+	}
+	name := p.Options.Args[0]
+	regs := p.registrationMap()
+
+	if p.Options.DryRun {
+		records, err := p.dryRunRecords()
+		if err != nil {
+			return err
+		}
+		if err := checkFake(name, appliedNames(records), regs, p.Options.Down); err != nil {
+			return err
+		}
+		state := "applied"
+		if p.Options.Down {
+			state = "not applied"
+		}
+		fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+fmt.Sprintf(" Would mark %s as %s without running it", name, state))
+		return nil
+	}
+
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+
+	applied, err := tracker.GetApplied()
+	if err != nil {
+		return err
+	}
+	if err := checkFake(name, applied, regs, p.Options.Down); err != nil {
+		return err
+	}
+
+	if p.Options.Down {
+		if err := tracker.RemoveMigration(name); err != nil {
+			return err
+		}
+		if err := tracker.logMarked(name, directionDown, 0, statusFake); err != nil {
+			return err
+		}
+		p.emit(EventRolledBack, name, directionDown, 0, 0, nil)
+		fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ Marked as not applied: %s", name)))
+		return nil
+	}
+
+	lastBatch, err := tracker.GetLastBatch()
+	if err != nil {
+		return err
+	}
+	batch := lastBatch + 1
+	if err := tracker.RecordMigration(name, batch, checksumOrEmpty(regs[name], p.Schema), 0); err != nil {
+		return err
+	}
+	if err := tracker.logMarked(name, directionUp, batch, statusFake); err != nil {
+		return err
+	}
+	p.emit(EventMigrated, name, directionUp, batch, 0, nil)
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ Marked as applied: %s", name)))
+	return nil
+}
@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+func TestSelectBaseline(t *testing.T) {
+	regs := []Registration{{Name: "001"}, {Name: "002"}, {Name: "003"}}
+
+	tests := []struct {
+		name    string
+		target  string
+		applied []string
+		want    []string
+	}{
+		{"empty database", "002", nil, []string{"001", "002"}},
+		{"partly applied", "003", []string{"001"}, []string{"002", "003"}},
+		{"already baselined", "002", []string{"001", "002"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marked, err := selectBaseline(tt.target, tt.applied, regs)
+			if err != nil {
+				t.Fatalf("selectBaseline() error: %v", err)
+			}
+			if got := regNames(marked); !slices.Equal(got, tt.want) {
+				t.Errorf("marked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := selectBaseline("999", nil, regs); err == nil {
+		t.Error("expected error for unknown migration")
+	}
+}
+
+func TestCheckFake(t *testing.T) {
+	regs := map[string]Registration{"001": {Name: "001"}, "002": {Name: "002"}}
+	applied := []string{"001", "000_deleted"}
+
+	tests := []struct {
+		name    string
+		target  string
+		down    bool
+		wantErr bool
+	}{
+		{"pending", "002", false, false},
+		{"already applied", "001", false, true},
+		{"not registered", "003", false, true},
+		{"down applied", "001", true, false},
+		{"down deleted migration", "000_deleted", true, false},
+		{"down not applied", "002", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkFake(tt.target, applied, regs, tt.down); (err != nil) != tt.wantErr {
+				t.Errorf("checkFake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunBaselineAndFake_History(t *testing.T) {
+	s, db := newFakeDB(t)
+	p := RunParams{Config: &config.Config{}, Registrations: testRegistrations(), Schema: s, out: io.Discard}
+	var events []string
+	p.onEvent = func(e Event) { events = append(events, e.Event+" "+e.Migration) }
+
+	p.Options = RunOptions{Args: []string{"001_users"}}
+	if err := RunBaseline(p); err != nil {
+		t.Fatalf("RunBaseline() error: %v", err)
+	}
+	p.Options = RunOptions{Args: []string{"002_posts"}, DryRun: true}
+	if err := RunFake(p); err != nil {
+		t.Fatalf("RunFake() dry run error: %v", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users"}) {
+		t.Fatalf("tracking table = %v after a dry run", got)
+	}
+	p.Options = RunOptions{Args: []string{"002_posts"}}
+	if err := RunFake(p); err != nil {
+		t.Fatalf("RunFake() error: %v", err)
+	}
+	p.Options = RunOptions{Args: []string{"002_posts"}, Down: true}
+	if err := RunFake(p); err != nil {
+		t.Fatalf("RunFake() down error: %v", err)
+	}
+
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	want := []fakeLogEntry{
+		{"001_users", directionUp, statusBaseline},
+		{"002_posts", directionUp, statusFake},
+		{"002_posts", directionDown, statusFake},
+	}
+	if !slices.Equal(db.state.history, want) {
+		t.Errorf("history = %+v, want %+v", db.state.history, want)
+	}
+	if len(db.state.executed) != 0 {
+		t.Errorf("executed = %q, want no migration statements", db.state.executed)
+	}
+	wantEvents := []string{"migrated 001_users", "migrated 002_posts", "rolled_back 002_posts"}
+	if !slices.Equal(events, wantEvents) {
+		t.Errorf("events = %q, want %q", events, wantEvents)
+	}
+}
@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Grandbusta/jone/schema"
+)
+
+// Checksum returns a SHA-256 checksum of the SQL that the migration's Up and
+// Down generate for the schema's dialect. The statements are recorded without
+// a connection, so the result does not depend on the state of the database;
+// alterations that need a table rebuild are hashed as a description of their actions.
+//
+// An error means the migration could not be recorded (for example, it queries
+// the database directly); such migrations are stored without a checksum.
+func Checksum(reg Registration, s *schema.Schema) (sum string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recording migration %s: %v", reg.Name, r)
+		}
+	}()
+
+	h := sha256.New()
+	for _, direction := range []struct {
+		name string
+		run  func(*schema.Schema) error
+	}{
+		{"up", reg.up},
+		{"down", func(s *schema.Schema) error { return run(s, reg.DownE, reg.Down) }}, // A missing Down hashes as empty
+	} {
+		rec := schema.NewRecorder()
+		rec.DescribeRebuilds()
+		if err := direction.run(s.WithRecorder(rec)); err != nil {
+			return "", fmt.Errorf("recording migration %s: %w", reg.Name, err)
+		}
+		fmt.Fprintf(h, "-- %s\n", direction.name)
+		for _, stmt := range rec.Statements() {
+			fmt.Fprintf(h, "%s\n%v\n", stmt.SQL, stmt.Args)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checksumOrEmpty returns the migration's checksum, or "" if it cannot be computed.
+func checksumOrEmpty(reg Registration, s *schema.Schema) string {
+	sum, err := Checksum(reg, s)
+	if err != nil {
+		return ""
+	}
+	return sum
+}
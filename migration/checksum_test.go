@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/schema"
+)
+
+func newTestSchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := schema.New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("schema.New() error: %v", err)
+	}
+	return s
+}
+
+func TestChecksum_Stable(t *testing.T) {
+	s := newTestSchema(t)
+	reg := Registration{
+		Name: "20260101000000_create_users",
+		Up: func(s *schema.Schema) {
+			s.CreateTable("users", func(t *schema.Table) { t.Increments("id") })
+		},
+		Down: func(s *schema.Schema) { s.DropTable("users") },
+	}
+
+	first, err := Checksum(reg, s)
+	if err != nil {
+		t.Fatalf("Checksum() error: %v", err)
+	}
+	second, _ := Checksum(reg, s)
+	if first != second || len(first) != 64 {
+		t.Errorf("Checksum() = %q then %q, want the same 64-char hex", first, second)
+	}
+
+	edited := reg
+	edited.Down = func(s *schema.Schema) { s.DropTableIfExists("users") }
+	if changed, _ := Checksum(edited, s); changed == first {
+		t.Error("editing Down did not change the checksum")
+	}
+}
+
+func TestChecksum_Unrecordable(t *testing.T) {
+	s := newTestSchema(t)
+
+	failing := Registration{Name: "a", UpE: func(*schema.Schema) error { return errors.New("needs data") }}
+	if _, err := Checksum(failing, s); err == nil {
+		t.Error("expected error from UpE")
+	}
+
+	panicking := Registration{Name: "b", Up: func(s *schema.Schema) { s.DB().Ping() }}
+	if _, err := Checksum(panicking, s); err == nil {
+		t.Error("expected error from a migration using the connection")
+	}
+	if got := checksumOrEmpty(panicking, s); got != "" {
+		t.Errorf("checksumOrEmpty() = %q, want empty", got)
+	}
+}
+
+func TestChecksum_SQLiteRebuild(t *testing.T) {
+	s, err := schema.New(&config.Config{Client: "sqlite3"})
+	if err != nil {
+		t.Fatalf("schema.New() error: %v", err)
+	}
+	reg := Registration{
+		Name: "20260101000000_require_email",
+		Up:   func(s *schema.Schema) { s.Table("users", func(t *schema.Table) { t.DropNullable("email") }) },
+		Down: func(s *schema.Schema) { s.Table("users", func(t *schema.Table) { t.SetNullable("email") }) },
+	}
+
+	sum, err := Checksum(reg, s)
+	if err != nil {
+		t.Fatalf("Checksum() error: %v", err)
+	}
+	edited := reg
+	edited.Up = func(s *schema.Schema) { s.Table("users", func(t *schema.Table) { t.DropNullable("name") }) }
+	if changed, _ := Checksum(edited, s); changed == sum {
+		t.Error("editing Up did not change the checksum")
+	}
+}
@@ -0,0 +1,359 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/schema"
+	"github.com/Grandbusta/jone/types"
+)
+
+// fakeDB is an in-memory stand-in for a PostgreSQL database, for tests that
+// run migrations through a real Tracker. It answers the tracker's queries,
+// matched against the SQL the dialect generates for them, and records every
+// other statement it executes. A statement containing FAIL returns an error.
+// Set lockHeld or lockRow to have another process hold the migration lock.
+type fakeDB struct {
+	mu      sync.Mutex
+	handle  map[string]func(args []driver.Value) (*fakeRows, error)
+	state   fakeState
+	txState *fakeState // State when the open transaction began
+	begins  int        // Transactions begun
+
+	// Migration lock, which transactions don't roll back
+	lockHeld    int  // Advisory lock attempts that find the lock held by another process
+	lockRow     bool // The lock table holds its row
+	lockInserts int  // Times the lock table row was inserted
+}
+
+type fakeState struct {
+	tables   map[string]bool // Tables that exist
+	missing  map[string]bool // Tracking columns not added yet, as in a table from an older version
+	records  []fakeRecord
+	history  []fakeLogEntry
+	executed []string // Statements other than tracking queries
+}
+
+type fakeRecord struct {
+	name     string
+	batch    int64
+	checksum any
+}
+
+type fakeLogEntry struct {
+	name, direction, status string
+}
+
+func (s fakeState) clone() fakeState {
+	return fakeState{
+		tables:   maps.Clone(s.tables),
+		missing:  maps.Clone(s.missing),
+		records:  slices.Clone(s.records),
+		history:  slices.Clone(s.history),
+		executed: slices.Clone(s.executed),
+	}
+}
+
+// newFakeDB returns a Schema connected to an empty fake database.
+func newFakeDB(t *testing.T) (*schema.Schema, *fakeDB) {
+	t.Helper()
+	s, err := schema.New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("schema.New() error: %v", err)
+	}
+	f := &fakeDB{state: fakeState{tables: map[string]bool{}, missing: map[string]bool{}}}
+	f.handle = f.handlers(s.Dialect(), "jone_migrations")
+
+	db := sql.OpenDB(fakeConnector{f})
+	t.Cleanup(func() { db.Close() })
+	s.SetDB(db)
+	return s, f
+}
+
+// createLegacyTable creates a tracking table as the first release of jone
+// did, holding records, with none of the columns added since.
+func (f *fakeDB) createLegacyTable(records ...fakeRecord) {
+	f.state.tables["jone_migrations"] = true
+	for _, col := range trackingColumns {
+		f.state.missing[col.Name] = true
+	}
+	f.state.records = records
+}
+
+func (f *fakeDB) appliedNames() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for _, rec := range f.state.records {
+		names = append(names, rec.name)
+	}
+	return names
+}
+
+func (f *fakeDB) handlers(d dialect.Dialect, table string) map[string]func([]driver.Value) (*fakeRows, error) {
+	tracking := dialect.Tracking(d)
+	logTable := table + "_log"
+	count := func(ok bool) (*fakeRows, error) {
+		if ok {
+			return rowsOf([]string{"count"}, []driver.Value{int64(1)}), nil
+		}
+		return rowsOf([]string{"count"}, []driver.Value{int64(0)}), nil
+	}
+	recordIndex := func(name any) int {
+		return slices.IndexFunc(f.state.records, func(r fakeRecord) bool { return r.name == name })
+	}
+
+	h := map[string]func([]driver.Value) (*fakeRows, error){
+		d.HasTableSQL("", table):    func([]driver.Value) (*fakeRows, error) { return count(f.state.tables[table]) },
+		d.HasTableSQL("", logTable): func([]driver.Value) (*fakeRows, error) { return count(f.state.tables[logTable]) },
+		d.CreateMigrationsTableSQL(table): func([]driver.Value) (*fakeRows, error) {
+			f.state.tables[table] = true
+			return nil, nil
+		},
+		tracking.CreateMigrationLogTableSQL(logTable): func([]driver.Value) (*fakeRows, error) {
+			f.state.tables[logTable] = true
+			return nil, nil
+		},
+		tracking.GetMigrationRecordsSQL(table): func([]driver.Value) (*fakeRows, error) {
+			if f.state.missing["checksum"] {
+				return nil, errors.New(`column "checksum" does not exist`)
+			}
+			rows := rowsOf([]string{"name", "batch", "checksum", "applied_at"})
+			for _, r := range f.state.records {
+				rows.add(r.name, r.batch, r.checksum, "2026-01-01 00:00:00")
+			}
+			return rows, nil
+		},
+		d.GetAppliedMigrationsSQL(table): func([]driver.Value) (*fakeRows, error) {
+			rows := rowsOf([]string{"name"})
+			for _, r := range f.state.records {
+				rows.add(r.name)
+			}
+			return rows, nil
+		},
+		d.GetLastBatchSQL(table): func([]driver.Value) (*fakeRows, error) {
+			var last int64
+			for _, r := range f.state.records {
+				last = max(last, r.batch)
+			}
+			return rowsOf([]string{"batch"}, []driver.Value{last}), nil
+		},
+		d.GetMigrationsByBatchSQL(table): func(args []driver.Value) (*fakeRows, error) {
+			rows := rowsOf([]string{"name"})
+			for i := len(f.state.records) - 1; i >= 0; i-- {
+				if r := f.state.records[i]; r.batch == args[0] {
+					rows.add(r.name)
+				}
+			}
+			return rows, nil
+		},
+		tracking.RecordMigrationSQL(table): func(args []driver.Value) (*fakeRows, error) {
+			if len(f.state.missing) > 0 {
+				return nil, errors.New(`column "checksum" does not exist`)
+			}
+			f.state.records = append(f.state.records, fakeRecord{name: args[0].(string), batch: args[1].(int64), checksum: args[2]})
+			return nil, nil
+		},
+		d.DeleteMigrationSQL(table): func(args []driver.Value) (*fakeRows, error) {
+			if i := recordIndex(args[0]); i >= 0 {
+				f.state.records = slices.Delete(f.state.records, i, i+1)
+			}
+			return nil, nil
+		},
+		tracking.UpdateMigrationChecksumSQL(table): func(args []driver.Value) (*fakeRows, error) {
+			if i := recordIndex(args[1]); i >= 0 {
+				f.state.records[i].checksum = args[0]
+			}
+			return nil, nil
+		},
+		tracking.InsertMigrationLogSQL(logTable): func(args []driver.Value) (*fakeRows, error) {
+			if !f.state.tables[logTable] {
+				return nil, fmt.Errorf("no such table: %s", logTable)
+			}
+			f.state.history = append(f.state.history, fakeLogEntry{name: args[0].(string), direction: args[1].(string), status: args[3].(string)})
+			return nil, nil
+		},
+	}
+	for _, col := range trackingColumns {
+		col := col
+		h[d.HasColumnSQL("", table, col.Name)] = func([]driver.Value) (*fakeRows, error) {
+			return count(!f.state.missing[col.Name])
+		}
+		h[tracking.AddMigrationsColumnSQL(table, col)] = func([]driver.Value) (*fakeRows, error) {
+			delete(f.state.missing, col.Name)
+			return nil, nil
+		}
+	}
+	lockTable := d.QualifyTable("", table+"_lock")
+	h[d.CreateTableIfNotExistsSQL(&types.Table{
+		Name:    table + "_lock",
+		Columns: []*types.Column{{Name: "id", DataType: "int", IsPrimaryKey: true, IsNotNull: true}},
+	})] = func([]driver.Value) (*fakeRows, error) { return nil, nil }
+	h[fmt.Sprintf("INSERT INTO %s (%s) VALUES (1);", lockTable, d.QuoteIdentifier("id"))] = func([]driver.Value) (*fakeRows, error) {
+		if f.lockRow {
+			return nil, errors.New("duplicate key value violates unique constraint")
+		}
+		f.lockRow = true
+		f.lockInserts++
+		return nil, nil
+	}
+	h[fmt.Sprintf("SELECT COUNT(*) FROM %s;", lockTable)] = func([]driver.Value) (*fakeRows, error) { return count(f.lockRow) }
+	h[fmt.Sprintf("DELETE FROM %s;", lockTable)] = func([]driver.Value) (*fakeRows, error) {
+		f.lockRow = false
+		return nil, nil
+	}
+	if locker, ok := d.(dialect.Locker); ok {
+		h[locker.TryLockSQL()] = func([]driver.Value) (*fakeRows, error) {
+			if f.lockHeld > 0 {
+				f.lockHeld--
+				return rowsOf([]string{"acquired"}, []driver.Value{int64(0)}), nil
+			}
+			return rowsOf([]string{"acquired"}, []driver.Value{int64(1)}), nil
+		}
+		h[locker.UnlockSQL()] = func([]driver.Value) (*fakeRows, error) { return nil, nil }
+	}
+	return h
+}
+
+// run executes query against the fake database.
+func (f *fakeDB) run(query string, args []driver.NamedValue) (*fakeRows, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	if handle, ok := f.handle[query]; ok {
+		return handle(values)
+	}
+	if strings.Contains(query, "FAIL") {
+		return nil, fmt.Errorf("syntax error in %q", query)
+	}
+	switch {
+	case strings.Contains(query, "FROM pg_tables"):
+		var names []string
+		for name := range f.state.tables {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		rows := rowsOf([]string{"tablename"})
+		for _, name := range names {
+			rows.add(name)
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM information_schema.views"), strings.Contains(query, "FROM pg_matviews"), strings.Contains(query, "FROM pg_type"):
+		return rowsOf([]string{"name"}), nil
+	}
+	if m := createTablePattern.FindStringSubmatch(query); m != nil {
+		f.state.tables[m[1]] = true
+	}
+	if m := dropTablePattern.FindStringSubmatch(query); m != nil {
+		delete(f.state.tables, m[1])
+		if m[1] == "jone_migrations" {
+			f.state.records, f.state.missing = nil, map[string]bool{}
+		}
+	}
+	f.state.executed = append(f.state.executed, query)
+	return nil, nil
+}
+
+// Statements that create and drop tables other than the tracking tables.
+var (
+	createTablePattern = regexp.MustCompile(`^CREATE TABLE "(\w+)"`)
+	dropTablePattern   = regexp.MustCompile(`^DROP TABLE (?:IF EXISTS )?(?:"\w+"\.)?"(\w+)"`)
+)
+
+type fakeConnector struct{ f *fakeDB }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ f *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake database doesn't prepare statements")
+}
+
+func (c fakeConn) Close() error { return nil }
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	if c.f.txState != nil {
+		return nil, errors.New("fake database allows one transaction at a time")
+	}
+	saved := c.f.state.clone()
+	c.f.txState = &saved
+	c.f.begins++
+	return fakeTx(c), nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, err := c.f.run(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.f.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return nil, fmt.Errorf("fake database can't answer query %q", query)
+	}
+	return rows, nil
+}
+
+type fakeTx struct{ f *fakeDB }
+
+func (tx fakeTx) Commit() error {
+	tx.f.mu.Lock()
+	defer tx.f.mu.Unlock()
+	tx.f.txState = nil
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.f.mu.Lock()
+	defer tx.f.mu.Unlock()
+	tx.f.state, tx.f.txState = *tx.f.txState, nil
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func rowsOf(columns []string, rows ...[]driver.Value) *fakeRows {
+	return &fakeRows{columns: columns, rows: rows}
+}
+
+func (r *fakeRows) add(values ...driver.Value) { r.rows = append(r.rows, values) }
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
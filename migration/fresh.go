@@ -0,0 +1,173 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// RunReset rolls back every applied migration through its Down.
+// Each migration is wrapped in a transaction.
+func RunReset(p RunParams) error {
+	if p.Options.DryRun {
+		p.Options.All = true
+		return runRollbackDryRun(p)
+	}
+
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+	return rollbackAll(p, tracker, p.registrationMap())
+}
+
+// RunRefresh rolls back every applied migration through its Down, then runs
+// all migrations again. Each migration is wrapped in a transaction.
+func RunRefresh(p RunParams) error {
+	if err := p.checkSingleTransaction(); err != nil {
+		return err
+	}
+
+	if p.Options.DryRun {
+		p.Options.All = true
+		if err := runRollbackDryRun(p); err != nil {
+			return err
+		}
+		fmt.Fprintln(p.stdout())
+		return printDryRunUp(p, p.Registrations)
+	}
+
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+	if err := rollbackAll(p, tracker, p.registrationMap()); err != nil {
+		return err
+	}
+	return runLatest(p, tracker)
+}
+
+// RunFresh drops every table, view and type in the configured schema without
+// running any Down, then runs all migrations. It refuses to run unless
+// Config.Environment names a non-production deployment or Options.Force is set.
+func RunFresh(p RunParams) error {
+	if p.Config.IsProduction() && !p.Options.Force {
+		return fmt.Errorf("migrate:fresh drops every table. Set Environment in jonefile.go to a non-production value (currently %q), or pass --force", p.Config.Environment)
+	}
+
+	if err := p.checkSingleTransaction(); err != nil {
+		return err
+	}
+
+	introspector, ok := p.Schema.Dialect().(dialect.Introspector)
+	if !ok {
+		return fmt.Errorf("migrate:fresh is not supported by the %s dialect", p.Schema.Dialect().Name())
+	}
+
+	tracker := p.newTracker()
+
+	if !p.Options.DryRun {
+		// Hold the migration lock for the whole run
+		unlock, err := p.lock(tracker)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	stmts, err := dropAllObjects(p, introspector, tracker)
+	if err != nil {
+		return err
+	}
+
+	if p.Options.DryRun {
+		fmt.Fprintln(p.stdout())
+		for _, reg := range p.Registrations {
+			fmt.Fprintf(p.stdout(), "Migration: %s\n", term.GreenText(reg.Name))
+			fmt.Fprintln(p.stdout(), "SQL:")
+			if err := p.printRecorded(reg.up); err != nil {
+				return fmt.Errorf("migration '%s' failed: %w", reg.Name, err)
+			}
+			fmt.Fprintln(p.stdout())
+		}
+		fmt.Fprintf(p.stdout(), "Total: %d statement(s) to drop objects, %d migration(s) would be applied\n", len(stmts), len(p.Registrations))
+		return nil
+	}
+
+	return runLatest(p, tracker)
+}
+
+// dropAllObjects drops every object in the configured schema except the lock
+// and history tables, or prints the statements in a dry run, and returns them.
+// Session settings such as disabled foreign key checks must apply to every
+// drop, so they all run on one connection, which is back in the pool when it
+// returns.
+func dropAllObjects(p RunParams, introspector dialect.Introspector, tracker *Tracker) ([]string, error) {
+	ctx := p.context()
+	conn, err := p.Schema.DB().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reserving connection: %w", err)
+	}
+	defer conn.Close()
+
+	stmts, err := introspector.DropAllObjectsSQL(connQueryer{ctx, conn}, p.Schema.SchemaName(), []string{tracker.lockName(), tracker.logTable()})
+	if err != nil {
+		return nil, fmt.Errorf("listing database objects: %w", err)
+	}
+
+	if p.Options.DryRun {
+		fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+" Would drop all objects and run every migration:")
+		fmt.Fprintln(p.stdout())
+		for _, stmt := range stmts {
+			fmt.Fprintln(p.stdout(), stmt)
+		}
+		return stmts, nil
+	}
+
+	fmt.Fprintln(p.stdout(), term.CyanText("Dropping all database objects..."))
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("dropping objects: %w\nstatement: %s", err, stmt)
+		}
+	}
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Dropped (%d statement(s))", len(stmts))))
+	return stmts, nil
+}
+
+// registrationMap returns the registrations keyed by name.
+func (p RunParams) registrationMap() map[string]Registration {
+	regMap := make(map[string]Registration, len(p.Registrations))
+	for _, reg := range p.Registrations {
+		regMap[reg.Name] = reg
+	}
+	return regMap
+}
+
+// connQueryer adapts a pinned connection to dialect.Queryer.
+type connQueryer struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (q connQueryer) Query(query string, args ...any) (*sql.Rows, error) {
+	return q.conn.QueryContext(q.ctx, query, args...)
+}
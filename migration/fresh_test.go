@@ -0,0 +1,127 @@
+package migration
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+func TestRunFresh_RefusesProduction(t *testing.T) {
+	for _, env := range []string{"", "production"} {
+		p := RunParams{Config: &config.Config{Client: "postgresql", Environment: env}}
+		err := RunFresh(p)
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Errorf("RunFresh() with Environment %q error = %v, want refusal", env, err)
+		}
+	}
+}
+
+// newFreshParams returns RunParams for a development database that has run
+// testRegistrations, and the fake database.
+func newFreshParams(t *testing.T) (RunParams, *fakeDB) {
+	t.Helper()
+	s, db := newFakeDB(t)
+	p := RunParams{Config: &config.Config{Environment: "development"}, Registrations: testRegistrations(), Schema: s, out: io.Discard}
+	if err := RunLatest(p); err != nil {
+		t.Fatalf("RunLatest() error: %v", err)
+	}
+	db.state.executed = nil
+	return p, db
+}
+
+func TestRunFresh(t *testing.T) {
+	p, db := newFreshParams(t)
+	db.state.tables["legacy"] = true               // Created outside migrations
+	db.state.tables["jone_migrations_lock"] = true // Kept, like the history table
+
+	if err := RunFresh(p); err != nil {
+		t.Fatalf("RunFresh() error: %v", err)
+	}
+
+	var drops []string
+	for _, stmt := range db.state.executed {
+		if strings.HasPrefix(stmt, "DROP") {
+			drops = append(drops, stmt)
+		}
+	}
+	want := []string{
+		`DROP TABLE IF EXISTS "public"."jone_migrations" CASCADE;`,
+		`DROP TABLE IF EXISTS "public"."legacy" CASCADE;`,
+		`DROP TABLE IF EXISTS "public"."posts" CASCADE;`,
+		`DROP TABLE IF EXISTS "public"."users" CASCADE;`,
+	}
+	if !slices.Equal(drops, want) {
+		t.Errorf("drops =\n%q\nwant\n%q", drops, want)
+	}
+	for _, table := range []string{"users", "posts", "jone_migrations", "jone_migrations_log", "jone_migrations_lock"} {
+		if !db.state.tables[table] {
+			t.Errorf("table %s missing after RunFresh()", table)
+		}
+	}
+	if db.state.tables["legacy"] {
+		t.Error("RunFresh() kept a table outside the keep list")
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	if len(db.state.history) != 4 {
+		t.Errorf("history = %+v, want the entries from before the drop kept", db.state.history)
+	}
+}
+
+func TestRunReset(t *testing.T) {
+	p, db := newFreshParams(t)
+
+	if err := RunReset(p); err != nil {
+		t.Fatalf("RunReset() error: %v", err)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want empty", got)
+	}
+	if want := []string{`DROP TABLE "posts";`, `DROP TABLE "users";`}; !slices.Equal(db.state.executed, want) {
+		t.Errorf("executed = %q, want %q", db.state.executed, want)
+	}
+}
+
+func TestRunRefresh(t *testing.T) {
+	p, db := newFreshParams(t)
+
+	if err := RunRefresh(p); err != nil {
+		t.Fatalf("RunRefresh() error: %v", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	want := []fakeLogEntry{
+		{"001_users", directionUp, statusSuccess},
+		{"002_posts", directionUp, statusSuccess},
+		{"002_posts", directionDown, statusSuccess},
+		{"001_users", directionDown, statusSuccess},
+		{"001_users", directionUp, statusSuccess},
+		{"002_posts", directionUp, statusSuccess},
+	}
+	if !slices.Equal(db.state.history, want) {
+		t.Errorf("history =\n%+v\nwant\n%+v", db.state.history, want)
+	}
+}
+
+func TestRunFresh_OneConnectionPool(t *testing.T) {
+	p, db := newFreshParams(t)
+	p.Schema.DB().SetMaxOpenConns(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p.Context = ctx
+
+	// The connection used for the drops must be back in the pool to migrate
+	if err := RunFresh(p); err != nil {
+		t.Fatalf("RunFresh() error: %v", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// RunHistory prints every logged up and down run, oldest first, as a table or,
+// with Options.Output set to OutputJSON, as a JSON array.
+func RunHistory(p RunParams) error {
+	tracker := p.newTracker()
+	exists, err := tracker.HistoryExists()
+	if err != nil {
+		return err
+	}
+	var entries []HistoryEntry
+	if exists {
+		if entries, err = tracker.GetHistory(); err != nil {
+			return err
+		}
+	}
+
+	if p.jsonOutput() {
+		if entries == nil {
+			entries = []HistoryEntry{} // Print [] rather than null
+		}
+		return writeJSON(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText("No migration history"))
+		return nil
+	}
+	return writeHistoryTable(p.stdout(), entries)
+}
+
+// writeHistoryTable writes entries as aligned columns.
+func writeHistoryTable(w io.Writer, entries []HistoryEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tMIGRATION\tDIRECTION\tBATCH\tSTATUS\tDURATION\tBY\tVERSION\tERROR")
+	for _, e := range entries {
+		batch := "-"
+		if e.Batch > 0 {
+			batch = fmt.Sprint(e.Batch)
+		}
+		by := e.AppliedBy
+		if e.Hostname != "" {
+			by += "@" + e.Hostname
+		}
+		errLine, _, _ := strings.Cut(e.Error, "\n")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.CreatedAt, e.Name, e.Direction, batch, e.Status,
+			time.Duration(e.DurationMs)*time.Millisecond, by, e.JoneVersion, errLine)
+	}
+	return tw.Flush()
+}
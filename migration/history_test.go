@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteHistoryTable(t *testing.T) {
+	entries := []HistoryEntry{
+		{Name: "001_users", Direction: "up", Batch: 1, Status: "success", DurationMs: 1500,
+			AppliedBy: "ana", Hostname: "ci-1", JoneVersion: "v0.2.0", CreatedAt: "2026-01-02 03:04:05"},
+		{Name: "001_users", Direction: "down", Status: "failed", DurationMs: 3,
+			AppliedBy: "ana", Error: "executing DROP TABLE: boom\nstatement: DROP TABLE users;", CreatedAt: "2026-01-03 00:00:00"},
+	}
+
+	var b strings.Builder
+	if err := writeHistoryTable(&b, entries); err != nil {
+		t.Fatalf("writeHistoryTable() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), b.String())
+	}
+	for _, want := range []string{"001_users", "up", "1.5s", "ana@ci-1", "v0.2.0"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row %q missing %q", lines[1], want)
+		}
+	}
+	if !strings.Contains(lines[2], "failed") || !strings.HasSuffix(lines[2], "executing DROP TABLE: boom") {
+		t.Errorf("failed row should show the first line of the error, got %q", lines[2])
+	}
+}
@@ -0,0 +1,158 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/Grandbusta/jone/types"
+)
+
+// lockRetryInterval is how often a waiting run retries the migration lock.
+const lockRetryInterval = 500 * time.Millisecond
+
+// ErrLockTimeout is returned when the migration lock is still held by another
+// process after the configured wait.
+var ErrLockTimeout = errors.New("timed out waiting for migration lock")
+
+// lockName returns the name of the migration lock (and of the fallback lock table).
+func (t *Tracker) lockName() string {
+	return t.tableName + "_lock"
+}
+
+// Lock takes the migration lock, waiting up to timeout for another process to
+// release it. The returned function releases the lock.
+//
+// Dialects implementing dialect.Locker use a session-level advisory lock held
+// on a dedicated connection. Other dialects, and pools limited to one open
+// connection, which the pinned connection would leave the run without, insert
+// a row into a lock table instead; if a process dies while holding it, delete
+// the row from <table>_lock by hand.
+func (t *Tracker) Lock(timeout time.Duration) (unlock func() error, err error) {
+	if locker, ok := t.dialect.(dialect.Locker); ok && t.db.Stats().MaxOpenConnections != 1 {
+		return t.lockAdvisory(locker, timeout)
+	}
+	return t.lockTable(timeout)
+}
+
+// lockAdvisory takes a dialect advisory lock on a pinned connection.
+func (t *Tracker) lockAdvisory(locker dialect.Locker, timeout time.Duration) (func() error, error) {
+	ctx := t.context()
+	conn, err := t.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reserving connection for migration lock: %w", err)
+	}
+
+	err = t.retryLock(timeout, func() (bool, error) {
+		var acquired int
+		if err := conn.QueryRowContext(ctx, locker.TryLockSQL(), t.lockName()).Scan(&acquired); err != nil {
+			return false, err
+		}
+		return acquired == 1, nil
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer conn.Close()
+		// Release even if the run was cancelled
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), locker.UnlockSQL(), t.lockName()); err != nil {
+			return fmt.Errorf("releasing migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// lockTable takes the lock by inserting the single row of the lock table.
+func (t *Tracker) lockTable(timeout time.Duration) (func() error, error) {
+	ctx := t.context()
+	table := &types.Table{
+		Name:    t.lockName(),
+		Columns: []*types.Column{{Name: "id", DataType: "int", IsPrimaryKey: true, IsNotNull: true}},
+	}
+	if _, err := t.db.ExecContext(ctx, t.dialect.CreateTableIfNotExistsSQL(table)); err != nil {
+		return nil, fmt.Errorf("failed to create lock table '%s': %w", table.Name, err)
+	}
+
+	qualified := t.dialect.QualifyTable("", table.Name)
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (1);", qualified, t.dialect.QuoteIdentifier("id"))
+	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM %s;", qualified)
+	deleteSQL := fmt.Sprintf("DELETE FROM %s;", qualified)
+
+	err := t.retryLock(timeout, func() (bool, error) {
+		_, insertErr := t.db.ExecContext(ctx, insertSQL)
+		if insertErr == nil {
+			return true, nil
+		}
+		// A failed insert means the lock is held only if the row exists
+		var count int
+		if err := t.db.QueryRowContext(ctx, countSQL).Scan(&count); err != nil || count == 0 {
+			return false, insertErr
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		if _, err := t.db.ExecContext(context.WithoutCancel(ctx), deleteSQL); err != nil {
+			return fmt.Errorf("releasing migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// retryLock calls try until it acquires the lock, fails, or timeout elapses.
+func (t *Tracker) retryLock(timeout time.Duration, try func() (bool, error)) error {
+	ctx := t.context()
+	deadline := time.Now().Add(timeout)
+	waiting := false
+
+	for {
+		acquired, err := try()
+		if err != nil {
+			return fmt.Errorf("acquiring migration lock: %w", err)
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w after %s. Another process may be running migrations", ErrLockTimeout, timeout)
+		}
+		if !waiting {
+			fmt.Fprintln(t.stdout(), term.YellowText("Waiting for another process to release the migration lock..."))
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("acquiring migration lock: %w", ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// lock takes the migration lock for a run using the configured timeout.
+// The returned function releases it and reports failures as a warning.
+func (p RunParams) lock(tracker *Tracker) (func(), error) {
+	timeout := p.Config.Migrations.LockTimeout
+	if timeout == 0 {
+		timeout = config.DefaultLockTimeout
+	}
+	unlock, err := tracker.Lock(timeout)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := unlock(); err != nil {
+			fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Warning: %v", err)))
+		}
+	}, nil
+}
@@ -0,0 +1,84 @@
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+// newLockTracker returns a tracker on a fake database, writing its output to out.
+func newLockTracker(t *testing.T, oneConnection bool, out *bytes.Buffer) (*Tracker, *fakeDB) {
+	t.Helper()
+	s, db := newFakeDB(t)
+	if oneConnection {
+		s.DB().SetMaxOpenConns(1)
+	}
+	p := RunParams{Config: &config.Config{}, Schema: s, out: out}
+	return p.newTracker(), db
+}
+
+func TestLock_AdvisoryHeld(t *testing.T) {
+	var out bytes.Buffer
+	tracker, db := newLockTracker(t, false, &out)
+	db.lockHeld = 100
+
+	if _, err := tracker.Lock(0); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("Lock() error = %v, want ErrLockTimeout", err)
+	}
+}
+
+func TestLock_AdvisoryWaitsForRelease(t *testing.T) {
+	var out bytes.Buffer
+	tracker, db := newLockTracker(t, false, &out)
+	db.lockHeld = 1
+
+	unlock, err := tracker.Lock(time.Minute)
+	if err != nil {
+		t.Fatalf("Lock() error: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("unlock() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Waiting for another process") {
+		t.Errorf("output = %q, want a waiting message", out.String())
+	}
+}
+
+func TestLock_TableHeld(t *testing.T) {
+	var out bytes.Buffer
+	tracker, db := newLockTracker(t, true, &out)
+	db.lockRow = true
+
+	if _, err := tracker.Lock(0); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("Lock() error = %v, want ErrLockTimeout", err)
+	}
+	if !db.lockRow || db.lockInserts != 0 {
+		t.Error("Lock() took or removed a lock row held by another process")
+	}
+}
+
+func TestLock_Table(t *testing.T) {
+	var out bytes.Buffer
+	tracker, db := newLockTracker(t, true, &out)
+
+	unlock, err := tracker.Lock(0)
+	if err != nil {
+		t.Fatalf("Lock() error: %v", err)
+	}
+	if !db.lockRow {
+		t.Fatal("Lock() didn't insert the lock row")
+	}
+	if _, err := tracker.Lock(0); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("second Lock() error = %v, want ErrLockTimeout", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("unlock() error: %v", err)
+	}
+	if db.lockRow {
+		t.Error("unlock() left the lock row")
+	}
+}
@@ -0,0 +1,185 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/schema"
+)
+
+// Migrator runs migrations from inside an application, for example on
+// startup, without the jone CLI. Its methods return what they did instead of
+// printing it, and log progress through a slog.Logger.
+//
+// Runs take the same migration lock as the CLI, so several instances of an
+// application can call Latest at once; one applies the migrations and the
+// others wait, then find nothing pending.
+type Migrator struct {
+	schema        *schema.Schema
+	config        *config.Config
+	registrations []Registration
+	logger        *slog.Logger
+	options       RunOptions
+}
+
+// MigratorOption configures a Migrator.
+type MigratorOption func(*Migrator)
+
+// WithLogger sets the logger. The default is slog.Default().
+func WithLogger(logger *slog.Logger) MigratorOption {
+	return func(m *Migrator) { m.logger = logger }
+}
+
+// WithAllowMissing lets Latest run when applied migrations are missing from
+// the registry, as happens while an older version of the application is still
+// deployed next to a newer one.
+func WithAllowMissing() MigratorOption {
+	return func(m *Migrator) { m.options.AllowMissing = true }
+}
+
+// NewMigrator creates a Migrator that runs registrations, in order, on db.
+// cfg.Client selects the dialect; its Connection and Pool are not used.
+func NewMigrator(db *sql.DB, cfg *config.Config, registrations []Registration, opts ...MigratorOption) (*Migrator, error) {
+	if db == nil {
+		return nil, fmt.Errorf("migrator needs a database connection")
+	}
+	s, err := schema.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.SetDB(db)
+
+	m := &Migrator{
+		schema:        s,
+		config:        cfg,
+		registrations: registrations,
+		logger:        slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Result lists the migrations a run applied or rolled back, in the order it
+// did so. Both are empty when there was nothing to do.
+type Result struct {
+	Applied    []Event
+	RolledBack []Event
+}
+
+// MigrationError is returned when a migration's Up or Down fails. Its
+// transaction, if it had one, was rolled back.
+type MigrationError struct {
+	Migration string
+	Direction string // "up" or "down"
+	Err       error
+}
+
+func (e *MigrationError) Error() string { return e.Err.Error() }
+
+func (e *MigrationError) Unwrap() error { return e.Err }
+
+// Latest applies every pending migration in one new batch, like migrate:latest.
+func (m *Migrator) Latest(ctx context.Context) (*Result, error) {
+	return m.run(ctx, RunLatest)
+}
+
+// Rollback rolls back the last batch, like migrate:rollback.
+func (m *Migrator) Rollback(ctx context.Context) (*Result, error) {
+	return m.run(ctx, RunRollback)
+}
+
+// To applies and rolls back migrations until name is the last one applied,
+// like migrate:to.
+func (m *Migrator) To(ctx context.Context, name string) (*Result, error) {
+	return m.run(ctx, RunTo, name)
+}
+
+// Status reports which migrations are applied and which are pending, like
+// migrate:list. It only queries the tracking table, so it neither creates it
+// nor upgrades one created by an older version of jone.
+func (m *Migrator) Status(ctx context.Context) (*ListReport, error) {
+	records, err := m.params(ctx, nil).dryRunRecords()
+	if err != nil {
+		return nil, err
+	}
+	report := buildListReport(m.params(ctx, nil), records)
+	return &report, nil
+}
+
+// params returns the RunParams for a run, with output going to the logger.
+func (m *Migrator) params(ctx context.Context, onEvent func(Event), args ...string) RunParams {
+	opts := m.options
+	opts.Args = args
+	return RunParams{
+		Config:        m.config,
+		Registrations: m.registrations,
+		Schema:        m.schema,
+		Options:       opts,
+		Context:       ctx,
+		out:           logWriter{ctx: ctx, logger: m.logger},
+		onEvent:       onEvent,
+	}
+}
+
+// run calls fn and collects the events it reports into a Result.
+func (m *Migrator) run(ctx context.Context, fn func(RunParams) error, args ...string) (*Result, error) {
+	result := &Result{}
+	var failed *Event
+	onEvent := func(e Event) {
+		attrs := []any{"migration", e.Migration, "direction", e.Direction, "duration_ms", e.DurationMs}
+		if e.Batch > 0 {
+			attrs = append(attrs, "batch", e.Batch)
+		}
+		switch e.Event {
+		case EventMigrated:
+			result.Applied = append(result.Applied, e)
+			m.logger.InfoContext(ctx, "migration applied", attrs...)
+		case EventRolledBack:
+			result.RolledBack = append(result.RolledBack, e)
+			m.logger.InfoContext(ctx, "migration rolled back", attrs...)
+		case EventFailed:
+			failed = &e
+			m.logger.ErrorContext(ctx, "migration failed", append(attrs, "error", e.Error)...)
+		}
+	}
+
+	if err := fn(m.params(ctx, onEvent, args...)); err != nil {
+		if failed != nil {
+			err = &MigrationError{Migration: failed.Migration, Direction: failed.Direction, Err: err}
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// ansiEscape matches the terminal color codes in progress messages.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// logWriter logs each line of a run's progress messages at debug level, or
+// warnings at warn level.
+type logWriter struct {
+	ctx    context.Context
+	logger *slog.Logger
+}
+
+func (w logWriter) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(ansiEscape.ReplaceAllString(string(b), ""), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		level := slog.LevelDebug
+		if strings.HasPrefix(line, "Warning") {
+			level = slog.LevelWarn
+		}
+		w.logger.Log(w.ctx, level, line)
+	}
+	return len(b), nil
+}
@@ -0,0 +1,265 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/Grandbusta/jone/schema"
+)
+
+func newTestMigrator(t *testing.T, logs *bytes.Buffer) *Migrator {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	m, err := NewMigrator(&sql.DB{}, &config.Config{Client: "postgresql"}, nil, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewMigrator() error: %v", err)
+	}
+	return m
+}
+
+func TestNewMigrator_RequiresDB(t *testing.T) {
+	if _, err := NewMigrator(nil, &config.Config{Client: "postgresql"}, nil); err == nil {
+		t.Error("expected error without a database")
+	}
+}
+
+func TestMigrator_CollectsEvents(t *testing.T) {
+	var logs bytes.Buffer
+	m := newTestMigrator(t, &logs)
+
+	result, err := m.run(context.Background(), func(p RunParams) error {
+		fmt.Fprintln(p.stdout(), term.CyanText("Running 2 migration(s) in batch 1..."))
+		p.emit(EventMigrated, "001_users", directionUp, 1, 0, nil)
+		p.emit(EventMigrated, "002_posts", directionUp, 1, 0, nil)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	if got := len(result.Applied); got != 2 || result.Applied[1].Migration != "002_posts" {
+		t.Errorf("Applied = %+v, want both migrations", result.Applied)
+	}
+	if len(result.RolledBack) != 0 {
+		t.Errorf("RolledBack = %+v, want none", result.RolledBack)
+	}
+	if out := logs.String(); !strings.Contains(out, `level=INFO msg="migration applied" migration=002_posts`) ||
+		!strings.Contains(out, `level=DEBUG msg="Running 2 migration(s) in batch 1..."`) {
+		t.Errorf("logs = %s", out)
+	}
+}
+
+func TestMigrator_WrapsFailure(t *testing.T) {
+	var logs bytes.Buffer
+	m := newTestMigrator(t, &logs)
+	cause := errors.New("syntax error")
+
+	_, err := m.run(context.Background(), func(p RunParams) error {
+		p.emit(EventFailed, "001_users", directionUp, 1, 0, cause)
+		return fmt.Errorf("migration '001_users' failed: %w", cause)
+	})
+
+	var migErr *MigrationError
+	if !errors.As(err, &migErr) {
+		t.Fatalf("error = %v, want *MigrationError", err)
+	}
+	if migErr.Migration != "001_users" || migErr.Direction != directionUp {
+		t.Errorf("MigrationError = %+v", migErr)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("MigrationError should unwrap to the cause")
+	}
+	if !strings.Contains(logs.String(), "level=ERROR") {
+		t.Errorf("logs = %s, want an error entry", logs.String())
+	}
+}
+
+func TestLogWriter_Levels(t *testing.T) {
+	var logs bytes.Buffer
+	w := logWriter{ctx: context.Background(), logger: slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+
+	fmt.Fprintln(w, term.YellowText("Warning: 1 applied migration(s) missing from the registry: 000_gone"))
+	fmt.Fprintln(w)
+
+	out := logs.String()
+	if strings.Count(out, "\n") != 1 {
+		t.Fatalf("logs = %q, want one entry", out)
+	}
+	if !strings.Contains(out, `level=WARN msg="Warning: 1 applied`) || strings.Contains(out, "\x1b[") {
+		t.Errorf("logs = %q, want a warning without color codes", out)
+	}
+}
+
+// testRegistrations creates a users table and then a posts table.
+func testRegistrations() []Registration {
+	return []Registration{
+		{
+			Name: "001_users",
+			Up:   func(s *schema.Schema) { s.CreateTable("users", func(t *schema.Table) { t.Increments("id") }) },
+			Down: func(s *schema.Schema) { s.DropTable("users") },
+		},
+		{
+			Name: "002_posts",
+			Up:   func(s *schema.Schema) { s.CreateTable("posts", func(t *schema.Table) { t.Increments("id") }) },
+			Down: func(s *schema.Schema) { s.DropTable("posts") },
+		},
+	}
+}
+
+// newFakeMigrator returns a Migrator running regs against a fake database.
+func newFakeMigrator(t *testing.T, regs []Registration) (*Migrator, *fakeDB) {
+	t.Helper()
+	s, db := newFakeDB(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	m, err := NewMigrator(s.DB(), &config.Config{Client: "postgresql"}, regs, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewMigrator() error: %v", err)
+	}
+	return m, db
+}
+
+func eventNames(events []Event) []string {
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Migration)
+	}
+	return names
+}
+
+func TestMigrator_LatestStatusRollback(t *testing.T) {
+	m, db := newFakeMigrator(t, testRegistrations())
+	ctx := context.Background()
+
+	result, err := m.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got := eventNames(result.Applied); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("Applied = %v", got)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	if len(db.state.executed) != 2 || !strings.HasPrefix(db.state.executed[0], `CREATE TABLE "users"`) {
+		t.Errorf("executed = %q", db.state.executed)
+	}
+
+	result, err = m.Latest(ctx)
+	if err != nil || len(result.Applied) != 0 {
+		t.Errorf("second Latest() = %+v, %v; want nothing applied", result, err)
+	}
+
+	report, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if report.Applied != 2 || report.Pending != 0 || report.Migrations[1].Batch != 1 {
+		t.Errorf("Status() = %+v", report)
+	}
+
+	result, err = m.Rollback(ctx)
+	if err != nil {
+		t.Fatalf("Rollback() error: %v", err)
+	}
+	if got := eventNames(result.RolledBack); !slices.Equal(got, []string{"002_posts", "001_users"}) {
+		t.Errorf("RolledBack = %v", got)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want empty", got)
+	}
+	if len(db.state.history) != 4 || db.state.history[3] != (fakeLogEntry{"001_users", directionDown, statusSuccess}) {
+		t.Errorf("history = %+v", db.state.history)
+	}
+}
+
+func TestMigrator_To(t *testing.T) {
+	m, db := newFakeMigrator(t, testRegistrations())
+	ctx := context.Background()
+
+	result, err := m.To(ctx, "001_users")
+	if err != nil {
+		t.Fatalf("To(001_users) error: %v", err)
+	}
+	if got := eventNames(result.Applied); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("Applied = %v", got)
+	}
+
+	if _, err := m.To(ctx, "002_posts"); err != nil {
+		t.Fatalf("To(002_posts) error: %v", err)
+	}
+	result, err = m.To(ctx, "001_users")
+	if err != nil {
+		t.Fatalf("To(001_users) error: %v", err)
+	}
+	if got := eventNames(result.RolledBack); !slices.Equal(got, []string{"002_posts"}) || len(result.Applied) != 0 {
+		t.Errorf("result = %+v, want only 002_posts rolled back", result)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+}
+
+func TestMigrator_FailedMigration(t *testing.T) {
+	regs := testRegistrations()
+	regs[1].Up = func(s *schema.Schema) { s.Raw("FAIL") }
+	m, db := newFakeMigrator(t, regs)
+
+	result, err := m.Latest(context.Background())
+	var migErr *MigrationError
+	if !errors.As(err, &migErr) || migErr.Migration != "002_posts" || migErr.Direction != directionUp {
+		t.Fatalf("Latest() error = %v, want a MigrationError for 002_posts", err)
+	}
+	if got := eventNames(result.Applied); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("Applied = %v", got)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("tracking table = %v, want the failed migration rolled back", got)
+	}
+	if last := db.state.history[len(db.state.history)-1]; last != (fakeLogEntry{"002_posts", directionUp, statusFailed}) {
+		t.Errorf("last history entry = %+v, want the failure", last)
+	}
+}
+
+func TestMigrator_StatusLegacyTable(t *testing.T) {
+	m, db := newFakeMigrator(t, testRegistrations())
+	db.createLegacyTable(fakeRecord{name: "001_users", batch: 1})
+
+	report, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if report.Applied != 1 || report.Pending != 1 {
+		t.Errorf("Status() = %+v, want one applied and one pending", report)
+	}
+	if len(db.state.missing) != len(trackingColumns) {
+		t.Errorf("Status() upgraded the tracking table")
+	}
+}
+
+func TestMigrator_OneConnectionPool(t *testing.T) {
+	m, db := newFakeMigrator(t, testRegistrations())
+	m.schema.DB().SetMaxOpenConns(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// An advisory lock would pin the only connection and block the run
+	if _, err := m.Latest(ctx); err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users", "002_posts"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	if db.lockInserts != 1 || db.lockRow {
+		t.Errorf("lock table inserted %d time(s), row left = %v; want it taken once and released", db.lockInserts, db.lockRow)
+	}
+}
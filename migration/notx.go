@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// runMigrationWithoutTx runs a migration with MigrationOptions.DisableTransaction
+// directly on the connection and records it once Up has succeeded.
+func runMigrationWithoutTx(p RunParams, tracker *Tracker, reg Registration, batch int) error {
+	fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("  ⚠ %s runs without a transaction; if it fails, its earlier statements stay applied", reg.Name)))
+
+	start := time.Now()
+	s := p.Schema.WithContext(p.context()).WithDB()
+	if err := reg.up(s); err != nil {
+		p.logFailure(tracker, reg.Name, directionUp, batch, time.Since(start), err)
+		return fmt.Errorf("migration '%s' failed without a transaction; statements before the failure were not rolled back and the migration was not recorded. Fix the database by hand before retrying: %w", reg.Name, err)
+	}
+
+	duration := time.Since(start)
+	if err := tracker.RecordMigration(reg.Name, batch, checksumOrEmpty(reg, p.Schema), duration); err != nil {
+		return fmt.Errorf("migration '%s' was applied but could not be recorded; record it by hand before retrying: %w", reg.Name, err)
+	}
+	if err := tracker.Log(reg.Name, directionUp, batch, duration, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Migrated: %s", reg.Name)))
+	p.emit(EventMigrated, reg.Name, directionUp, batch, duration, nil)
+	return nil
+}
+
+// rollbackMigrationWithoutTx runs Down of a migration with
+// MigrationOptions.DisableTransaction directly on the connection and removes
+// its record once Down has succeeded.
+func rollbackMigrationWithoutTx(p RunParams, tracker *Tracker, reg Registration) error {
+	fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("  ⚠ %s rolls back without a transaction; if it fails, its earlier statements stay applied", reg.Name)))
+
+	start := time.Now()
+	s := p.Schema.WithContext(p.context()).WithDB()
+	if err := reg.down(s); err != nil {
+		p.logFailure(tracker, reg.Name, directionDown, 0, time.Since(start), err)
+		return fmt.Errorf("rollback of '%s' failed without a transaction; statements before the failure were not rolled back and the migration is still recorded. Fix the database by hand before retrying: %w", reg.Name, err)
+	}
+	duration := time.Since(start)
+
+	if err := tracker.RemoveMigration(reg.Name); err != nil {
+		return fmt.Errorf("migration '%s' was rolled back but its record could not be removed; remove it by hand before retrying: %w", reg.Name, err)
+	}
+	if err := tracker.Log(reg.Name, directionDown, 0, duration, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Rolled back: %s", reg.Name)))
+	p.emit(EventRolledBack, reg.Name, directionDown, 0, duration, nil)
+	return nil
+}
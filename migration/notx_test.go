@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/schema"
+)
+
+func TestRunMigrationWithoutTx(t *testing.T) {
+	s, db := newFakeDB(t)
+	var recordedDuringUp []string
+	regs := []Registration{{
+		Name: "001_index",
+		Up: func(s *schema.Schema) {
+			s.Raw("CREATE INDEX CONCURRENTLY users_email ON users (email)")
+			recordedDuringUp = db.appliedNames()
+		},
+		Down:    func(s *schema.Schema) { s.Raw("DROP INDEX CONCURRENTLY users_email") },
+		Options: MigrationOptions{DisableTransaction: true},
+	}}
+	p := RunParams{Config: &config.Config{}, Registrations: regs, Schema: s, out: io.Discard}
+
+	if err := RunLatest(p); err != nil {
+		t.Fatalf("RunLatest() error: %v", err)
+	}
+	if db.begins != 0 {
+		t.Errorf("began %d transaction(s), want none", db.begins)
+	}
+	if len(recordedDuringUp) != 0 {
+		t.Errorf("tracking table during Up = %v, want the record written after it", recordedDuringUp)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_index"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+
+	if err := RunRollback(p); err != nil {
+		t.Fatalf("RunRollback() error: %v", err)
+	}
+	if db.begins != 0 {
+		t.Errorf("began %d transaction(s), want none", db.begins)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want empty", got)
+	}
+	if want := []string{"CREATE INDEX CONCURRENTLY users_email ON users (email)", "DROP INDEX CONCURRENTLY users_email"}; !slices.Equal(db.state.executed, want) {
+		t.Errorf("executed = %q, want %q", db.state.executed, want)
+	}
+}
+
+func TestRunMigrationWithoutTx_Failure(t *testing.T) {
+	s, db := newFakeDB(t)
+	regs := []Registration{{
+		Name: "001_index",
+		Up: func(s *schema.Schema) {
+			s.Raw("CREATE INDEX CONCURRENTLY users_email ON users (email)")
+			s.Raw("FAIL")
+		},
+		Down:    func(s *schema.Schema) { s.Raw("FAIL") },
+		Options: MigrationOptions{DisableTransaction: true},
+	}}
+	p := RunParams{Config: &config.Config{}, Registrations: regs, Schema: s, out: io.Discard}
+
+	if err := RunLatest(p); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Fatalf("RunLatest() error = %v, want the migration to fail unrecorded", err)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want nothing recorded", got)
+	}
+	// Without a transaction, the statement before the failure stays applied
+	if want := []string{"CREATE INDEX CONCURRENTLY users_email ON users (email)"}; !slices.Equal(db.state.executed, want) {
+		t.Errorf("executed = %q, want %q", db.state.executed, want)
+	}
+	if want := []fakeLogEntry{{"001_index", directionUp, statusFailed}}; !slices.Equal(db.state.history, want) {
+		t.Errorf("history = %+v, want %+v", db.state.history, want)
+	}
+
+	db.state.records = []fakeRecord{{name: "001_index", batch: 1}}
+	if err := RunRollback(p); err == nil || !strings.Contains(err.Error(), "still recorded") {
+		t.Fatalf("RunRollback() error = %v, want the rollback to fail", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_index"}) {
+		t.Errorf("tracking table = %v, want the record kept", got)
+	}
+}
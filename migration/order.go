@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// migrationVersion returns the timestamp prefix of a migration name
+// (e.g. "20260114035749" for "20260114035749_add_users").
+func migrationVersion(name string) string {
+	if idx := strings.Index(name, "_"); idx > 0 {
+		return name[:idx]
+	}
+	return name
+}
+
+// outOfOrder returns the pending migrations whose version is older than the
+// newest applied migration, in registry order.
+func outOfOrder(applied []string, pending []Registration) []string {
+	newest := ""
+	for _, name := range applied {
+		if v := migrationVersion(name); v > newest {
+			newest = v
+		}
+	}
+
+	var names []string
+	for _, reg := range pending {
+		if migrationVersion(reg.Name) < newest {
+			names = append(names, reg.Name)
+		}
+	}
+	return names
+}
+
+// checkOutOfOrder applies the configured Migrations.OutOfOrder policy to the
+// pending migrations.
+func checkOutOfOrder(p RunParams, applied []string, pending []Registration) error {
+	policy := p.Config.Migrations.OutOfOrder
+	switch policy {
+	case "", config.OutOfOrderWarn, config.OutOfOrderError, config.OutOfOrderAllow:
+	default:
+		return fmt.Errorf("invalid Migrations.OutOfOrder %q in jonefile.go (want %q, %q or %q)",
+			policy, config.OutOfOrderError, config.OutOfOrderWarn, config.OutOfOrderAllow)
+	}
+
+	names := outOfOrder(applied, pending)
+	if len(names) == 0 || policy == config.OutOfOrderAllow {
+		return nil
+	}
+	if policy == config.OutOfOrderError {
+		return fmt.Errorf("%d pending migration(s) are older than the newest applied migration: %s. Set Migrations.OutOfOrder to \"warn\" or \"allow\" to run them",
+			len(names), strings.Join(names, ", "))
+	}
+	fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Warning: running %d migration(s) older than the newest applied migration: %s", len(names), strings.Join(names, ", "))))
+	return nil
+}
@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+func TestOutOfOrder(t *testing.T) {
+	applied := []string{"20260101000000_users", "20260301000000_posts"}
+	pending := []Registration{
+		{Name: "20260201000000_merged_from_branch"},
+		{Name: "20260401000000_comments"},
+	}
+
+	got := outOfOrder(applied, pending)
+	want := []string{"20260201000000_merged_from_branch"}
+	if !slices.Equal(got, want) {
+		t.Errorf("outOfOrder() = %v, want %v", got, want)
+	}
+
+	if got := outOfOrder(nil, pending); len(got) != 0 {
+		t.Errorf("outOfOrder() with nothing applied = %v, want none", got)
+	}
+}
+
+func TestCheckOutOfOrder_Policy(t *testing.T) {
+	applied := []string{"20260301000000_posts"}
+	pending := []Registration{{Name: "20260201000000_late"}}
+
+	tests := []struct {
+		policy  string
+		wantErr string
+	}{
+		{"", ""},
+		{config.OutOfOrderWarn, ""},
+		{config.OutOfOrderAllow, ""},
+		{config.OutOfOrderError, "20260201000000_late"},
+		{"sometimes", "invalid Migrations.OutOfOrder"},
+	}
+
+	for _, tt := range tests {
+		p := RunParams{Config: &config.Config{Migrations: config.Migrations{OutOfOrder: tt.policy}}}
+		err := checkOutOfOrder(p, applied, pending)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("policy %q: unexpected error %v", tt.policy, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("policy %q: error = %v, want it to contain %q", tt.policy, err, tt.wantErr)
+		}
+	}
+}
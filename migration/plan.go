@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Grandbusta/jone/schema"
+)
+
+// RunPlan is the SQL a migrate:latest run would execute.
+type RunPlan struct {
+	Migrations []string           // Pending migrations, in order
+	Statements []schema.Statement // Their statements in order, each tagged with its migration
+}
+
+// Plan returns the statements RunLatest would execute, without running them.
+// With a connection it reads the tracking table and plans only the pending
+// migrations; without one every registration is planned. Statements are
+// recorded, not executed.
+func Plan(p RunParams) (*RunPlan, error) {
+	var applied []string
+	if p.Schema.DB() != nil {
+		records, err := p.dryRunRecords()
+		if err != nil {
+			return nil, err
+		}
+		applied = appliedNames(records)
+	}
+	return planUp(p.Schema, pendingRegistrations(applied, p.Registrations))
+}
+
+// pendingRegistrations returns the registrations not in applied, in order.
+func pendingRegistrations(applied []string, regs []Registration) []Registration {
+	var pending []Registration
+	for _, reg := range regs {
+		if !slices.Contains(applied, reg.Name) {
+			pending = append(pending, reg)
+		}
+	}
+	return pending
+}
+
+// planUp records the Up statements of regs against a recording copy of s.
+func planUp(s *schema.Schema, regs []Registration) (*RunPlan, error) {
+	rec := schema.NewRecorder()
+	plan := &RunPlan{}
+	for _, reg := range regs {
+		rec.SetMigration(reg.Name)
+		if err := reg.up(s.WithRecorder(rec)); err != nil {
+			return nil, fmt.Errorf("migration '%s' failed: %w", reg.Name, err)
+		}
+		plan.Migrations = append(plan.Migrations, reg.Name)
+	}
+	plan.Statements = rec.Statements()
+	return plan, nil
+}
+
+// MigrationStatements returns the planned statements of the named migration.
+func (p *RunPlan) MigrationStatements(name string) []schema.Statement {
+	var stmts []schema.Statement
+	for _, stmt := range p.Statements {
+		if stmt.Migration == name {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
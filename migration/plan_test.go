@@ -0,0 +1,45 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/Grandbusta/jone/schema"
+)
+
+func TestPlan_WithoutConnection(t *testing.T) {
+	p := RunParams{
+		Schema: newTestSchema(t),
+		Registrations: []Registration{
+			{
+				Name: "001_users",
+				Up: func(s *schema.Schema) {
+					s.CreateTable("users", func(t *schema.Table) {
+						t.Increments("id")
+						t.String("email").Comment("login")
+					})
+				},
+			},
+			{
+				Name: "002_seed",
+				Up:   func(s *schema.Schema) { s.Raw("INSERT INTO users (email) VALUES ($1)", "a@b.c") },
+			},
+		},
+	}
+
+	plan, err := Plan(p)
+	if err != nil {
+		t.Fatalf("Plan() error: %v", err)
+	}
+	if len(plan.Migrations) != 2 {
+		t.Fatalf("Migrations = %v, want both", plan.Migrations)
+	}
+
+	users := plan.MigrationStatements("001_users")
+	if len(users) != 2 || users[1].Kind != "COMMENT ON COLUMN" || users[1].Table != "users" {
+		t.Errorf("001_users statements = %+v, want CREATE TABLE and COMMENT ON COLUMN on users", users)
+	}
+	seed := plan.MigrationStatements("002_seed")
+	if len(seed) != 1 || seed[0].Kind != "raw SQL" || len(seed[0].Args) != 1 {
+		t.Errorf("002_seed statements = %+v", seed)
+	}
+}
@@ -1,11 +1,61 @@
 // Package migration provides migration registration and execution.
 package migration
 
-import "github.com/Grandbusta/jone/schema"
+import (
+	"fmt"
+
+	"github.com/Grandbusta/jone/schema"
+)
 
 // Registration represents a single migration with its metadata and operations.
+//
+// Up and Down take the classic func(*schema.Schema) form; errors from schema
+// operations are collected on the Schema and checked by the runner afterwards.
+// UpE and DownE are the error-returning form and take precedence when set.
 type Registration struct {
-	Name string
-	Up   func(*schema.Schema)
-	Down func(*schema.Schema)
+	Name    string
+	Up      func(*schema.Schema)
+	Down    func(*schema.Schema)
+	UpE     func(*schema.Schema) error
+	DownE   func(*schema.Schema) error
+	Options MigrationOptions
+}
+
+// MigrationOptions changes how a single migration is run. A migration declares
+// them as a package-level variable, which the generated registry picks up:
+//
+//	var Options = jone.MigrationOptions{DisableTransaction: true}
+type MigrationOptions struct {
+	// DisableTransaction runs Up and Down directly on the connection instead of
+	// in a transaction, for statements that cannot run inside one, such as
+	// PostgreSQL's CREATE INDEX CONCURRENTLY. If the migration fails part way,
+	// the statements that already ran are not rolled back.
+	DisableTransaction bool
+}
+
+// up runs the Up migration and returns its error or the first schema error.
+func (r Registration) up(s *schema.Schema) error {
+	return run(s, r.UpE, r.Up)
+}
+
+// down runs the Down migration and returns its error or the first schema error.
+// A migration without one, such as a SQL migration without a down.sql, can't
+// be rolled back; rather than only deleting its record, down fails.
+func (r Registration) down(s *schema.Schema) error {
+	if r.DownE == nil && r.Down == nil {
+		return fmt.Errorf("migration %s has no down migration", r.Name)
+	}
+	return run(s, r.DownE, r.Down)
+}
+
+func run(s *schema.Schema, withErr func(*schema.Schema) error, plain func(*schema.Schema)) error {
+	switch {
+	case withErr != nil:
+		if err := withErr(s); err != nil {
+			return err
+		}
+	case plain != nil:
+		plain(s)
+	}
+	return s.Err()
 }
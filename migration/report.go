@@ -0,0 +1,200 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// Output formats for Options.Output.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Event kinds printed with --output json.
+const (
+	EventMigrated   = "migrated"
+	EventRolledBack = "rolled_back"
+	EventFailed     = "failed"
+)
+
+// Event is a structured record of a migration applied or rolled back by a run,
+// including one only marked as such by migrate:baseline or migrate:fake.
+// With Options.Output set to OutputJSON, each one is printed to stdout as a
+// line of JSON.
+type Event struct {
+	Event      string `json:"event"`
+	Migration  string `json:"migration"`
+	Direction  string `json:"direction"` // "up" or "down"
+	Batch      int    `json:"batch,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	Time       string `json:"time"`
+}
+
+// jsonOutput reports whether the run prints JSON. Every check of
+// Options.Output goes through it, so the format is matched in one place.
+func (p RunParams) jsonOutput() bool {
+	return strings.EqualFold(strings.TrimSpace(p.Options.Output), OutputJSON)
+}
+
+// stdout returns where human-readable progress goes: stdout, or stderr when
+// stdout is reserved for JSON.
+func (p RunParams) stdout() io.Writer {
+	if p.out != nil {
+		return p.out
+	}
+	if p.jsonOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// emit reports an event to Migrator and, when the run prints JSON, prints it
+// as a line of JSON.
+func (p RunParams) emit(kind, name, direction string, batch int, duration time.Duration, runErr error) {
+	if !p.jsonOutput() && p.onEvent == nil {
+		return
+	}
+	e := Event{
+		Event:      kind,
+		Migration:  name,
+		Direction:  direction,
+		Batch:      batch,
+		DurationMs: duration.Milliseconds(),
+		Time:       time.Now().UTC().Format(time.RFC3339),
+	}
+	if runErr != nil {
+		e.Error = runErr.Error()
+	}
+	if p.onEvent != nil {
+		p.onEvent(e)
+	}
+	if p.jsonOutput() {
+		json.NewEncoder(os.Stdout).Encode(e)
+	}
+}
+
+// writeJSON prints v to stdout as indented JSON.
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Migration statuses reported by migrate:list.
+const (
+	StatusApplied = "applied"
+	StatusPending = "pending"
+	StatusMissing = "missing" // Applied, but no longer in the registry
+)
+
+// ListEntry is one migration in a ListReport.
+type ListEntry struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Batch      int    `json:"batch,omitempty"`
+	AppliedAt  string `json:"applied_at,omitempty"`
+	Changed    bool   `json:"changed,omitempty"`      // Edited since it was applied
+	OutOfOrder bool   `json:"out_of_order,omitempty"` // Pending, but older than an applied migration
+}
+
+// ListReport is what migrate:list prints: registered migrations in order,
+// followed by applied ones missing from the registry.
+type ListReport struct {
+	Migrations []ListEntry `json:"migrations"`
+	Applied    int         `json:"applied"`
+	Pending    int         `json:"pending"`
+	Missing    int         `json:"missing"`
+	Changed    int         `json:"changed"`
+}
+
+// buildListReport combines the registry with the applied records.
+func buildListReport(p RunParams, records []AppliedMigration) ListReport {
+	recordSet := make(map[string]AppliedMigration, len(records))
+	for _, rec := range records {
+		recordSet[rec.Name] = rec
+	}
+
+	// Applied migrations edited since they ran
+	changedSet := make(map[string]bool)
+	for _, d := range findChecksumDrift(p, records) {
+		if d.Recorded {
+			changedSet[d.Name] = true
+		}
+	}
+
+	// Pending migrations older than the newest applied one
+	applied := appliedNames(records)
+	var pending []Registration
+	for _, reg := range p.Registrations {
+		if _, ok := recordSet[reg.Name]; !ok {
+			pending = append(pending, reg)
+		}
+	}
+	lateSet := make(map[string]bool)
+	for _, name := range outOfOrder(applied, pending) {
+		lateSet[name] = true
+	}
+
+	report := ListReport{Migrations: []ListEntry{}}
+	for _, reg := range p.Registrations {
+		entry := ListEntry{Name: reg.Name, Status: StatusPending, OutOfOrder: lateSet[reg.Name]}
+		if rec, ok := recordSet[reg.Name]; ok {
+			entry = ListEntry{Name: reg.Name, Status: StatusApplied, Batch: rec.Batch, AppliedAt: rec.AppliedAt, Changed: changedSet[reg.Name]}
+			report.Applied++
+			if entry.Changed {
+				report.Changed++
+			}
+		} else {
+			report.Pending++
+		}
+		report.Migrations = append(report.Migrations, entry)
+	}
+
+	// Applied migrations with no registration (deleted or renamed)
+	for _, name := range missingFromRegistry(applied, p.Registrations) {
+		rec := recordSet[name]
+		report.Migrations = append(report.Migrations, ListEntry{Name: name, Status: StatusMissing, Batch: rec.Batch, AppliedAt: rec.AppliedAt})
+		report.Missing++
+	}
+	return report
+}
+
+// writeList prints report as the human-readable migrate:list output.
+func writeList(w io.Writer, report ListReport) {
+	fmt.Fprintln(w, "\nMigrations:")
+	fmt.Fprintln(w, "───────────────────────────────────────────────────────")
+
+	for _, e := range report.Migrations {
+		switch {
+		case e.Status == StatusMissing:
+			fmt.Fprintf(w, "  %s  %s %s\n", term.RedText("✗"), e.Name, term.RedText("(applied, missing from registry)"))
+		case e.Changed:
+			fmt.Fprintf(w, "  %s  %s %s\n", term.GreenText("✓"), e.Name, term.YellowText("(changed since applied)"))
+		case e.Status == StatusApplied:
+			fmt.Fprintf(w, "  %s  %s\n", term.GreenText("✓"), e.Name)
+		case e.OutOfOrder:
+			fmt.Fprintf(w, "  %s  %s %s\n", term.YellowText("○"), e.Name, term.YellowText("(out of order)"))
+		default:
+			fmt.Fprintf(w, "  %s  %s\n", term.YellowText("○"), e.Name)
+		}
+	}
+
+	fmt.Fprintln(w, "───────────────────────────────────────────────────────")
+	if report.Missing > 0 {
+		fmt.Fprintf(w, "Total: %s, %s, %s\n\n", term.GreenText(fmt.Sprintf("%d applied", report.Applied)), term.YellowText(fmt.Sprintf("%d pending", report.Pending)), term.RedText(fmt.Sprintf("%d missing", report.Missing)))
+	} else {
+		fmt.Fprintf(w, "Total: %s, %s\n\n", term.GreenText(fmt.Sprintf("%d applied", report.Applied)), term.YellowText(fmt.Sprintf("%d pending", report.Pending)))
+	}
+
+	if report.Changed > 0 {
+		fmt.Fprintln(w, term.YellowText(fmt.Sprintf("Warning: %d applied migration(s) changed since they ran. See migrate:validate\n", report.Changed)))
+	}
+}
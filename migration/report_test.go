@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Grandbusta/jone/schema"
+)
+
+func TestBuildListReport(t *testing.T) {
+	up := func(s *schema.Schema) { s.Raw("SELECT 1") }
+	p := RunParams{
+		Schema: newTestSchema(t),
+		Registrations: []Registration{
+			{Name: "001_users", Up: up},
+			{Name: "002_posts", Up: up},
+			{Name: "003_tags", Up: up},
+		},
+	}
+	sum := checksumOrEmpty(p.Registrations[0], p.Schema)
+	records := []AppliedMigration{
+		{Name: "001_users", Batch: 1, Checksum: sum, AppliedAt: "2026-01-02T03:04:05Z"},
+		{Name: "003_tags", Batch: 2, Checksum: "stale"},
+		{Name: "004_gone", Batch: 2},
+	}
+
+	report := buildListReport(p, records)
+	if report.Applied != 2 || report.Pending != 1 || report.Missing != 1 || report.Changed != 1 {
+		t.Fatalf("counts = %+v, want 2 applied, 1 pending, 1 missing, 1 changed", report)
+	}
+	want := []ListEntry{
+		{Name: "001_users", Status: StatusApplied, Batch: 1, AppliedAt: "2026-01-02T03:04:05Z"},
+		{Name: "002_posts", Status: StatusPending, OutOfOrder: true},
+		{Name: "003_tags", Status: StatusApplied, Batch: 2, Changed: true},
+		{Name: "004_gone", Status: StatusMissing, Batch: 2},
+	}
+	if len(report.Migrations) != len(want) {
+		t.Fatalf("Migrations = %+v, want %+v", report.Migrations, want)
+	}
+	for i := range want {
+		if report.Migrations[i] != want[i] {
+			t.Errorf("Migrations[%d] = %+v, want %+v", i, report.Migrations[i], want[i])
+		}
+	}
+}
+
+func TestEmit(t *testing.T) {
+	out := captureStdout(t, func() {
+		p := RunParams{Options: RunOptions{Output: OutputJSON}}
+		p.emit(EventMigrated, "001_users", directionUp, 3, 1500*time.Millisecond, nil)
+		p.emit(EventFailed, "002_posts", directionUp, 3, 0, errors.New("boom"))
+		RunParams{}.emit(EventMigrated, "003_tags", directionUp, 3, 0, nil) // Text output: no event
+	})
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	var events []Event
+	for dec.More() {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("decoding %q: %v", out, err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %s", len(events), out)
+	}
+	if e := events[0]; e.Event != EventMigrated || e.Migration != "001_users" || e.Batch != 3 || e.DurationMs != 1500 || e.Error != "" {
+		t.Errorf("events[0] = %+v", e)
+	}
+	if e := events[1]; e.Event != EventFailed || e.Error != "boom" {
+		t.Errorf("events[1] = %+v", e)
+	}
+}
+
+func TestStdout_JSONGoesToStderr(t *testing.T) {
+	if (RunParams{}).stdout() != os.Stdout {
+		t.Error("text output should print progress to stdout")
+	}
+	if (RunParams{Options: RunOptions{Output: OutputJSON}}).stdout() != os.Stderr {
+		t.Error("JSON output should print progress to stderr")
+	}
+	if (RunParams{Options: RunOptions{Output: " JSON"}}).stdout() != os.Stderr {
+		t.Error("output format should be matched case-insensitively")
+	}
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// validateRollbackTarget checks that at most one rollback target is given.
+func (o RunOptions) validateRollbackTarget() error {
+	if o.Step < 0 || o.Batch < 0 || o.ToBatch < 0 {
+		return fmt.Errorf("--step, --batch and --to-batch must be positive")
+	}
+	set := 0
+	for _, on := range []bool{o.All, o.Step > 0, o.Batch > 0, o.ToBatch > 0} {
+		if on {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("use only one of --all, --step, --batch and --to-batch")
+	}
+	return nil
+}
+
+// rollbackSelectsByRecord reports whether the rollback targets are chosen from
+// the tracking table rows (--step, --batch or --to-batch).
+func (o RunOptions) rollbackSelectsByRecord() bool {
+	return o.Step > 0 || o.Batch > 0 || o.ToBatch > 0
+}
+
+// selectRollback returns the applied migrations a rollback with o would undo,
+// most recently applied first. Without a target that is the last batch.
+func selectRollback(records []AppliedMigration, o RunOptions) []string {
+	lastBatch := 0
+	for _, rec := range records {
+		lastBatch = max(lastBatch, rec.Batch)
+	}
+
+	var names []string
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		switch {
+		case o.All:
+		case o.Step > 0:
+			if len(names) == o.Step {
+				return names
+			}
+		case o.Batch > 0:
+			if rec.Batch != o.Batch {
+				continue
+			}
+		case o.ToBatch > 0:
+			if rec.Batch <= o.ToBatch {
+				continue
+			}
+		default:
+			if rec.Batch != lastBatch {
+				continue
+			}
+		}
+		names = append(names, rec.Name)
+	}
+	return names
+}
+
+// describeRollback describes the selected rollback target for messages.
+func describeRollback(o RunOptions) string {
+	switch {
+	case o.Step > 0:
+		return fmt.Sprintf("the last %d migration(s)", o.Step)
+	case o.Batch > 0:
+		return fmt.Sprintf("batch %d", o.Batch)
+	default:
+		return fmt.Sprintf("batches above %d", o.ToBatch)
+	}
+}
+
+// rollbackSelected rolls back the migrations chosen by Step, Batch or ToBatch.
+func rollbackSelected(p RunParams, tracker *Tracker, regMap map[string]Registration) error {
+	records, err := tracker.GetRecords()
+	if err != nil {
+		return fmt.Errorf("getting applied migrations: %w", err)
+	}
+
+	names := selectRollback(records, p.Options)
+	if len(names) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText("Nothing to rollback."))
+		return nil
+	}
+
+	fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Rolling back %d migration(s) from %s...", len(names), describeRollback(p.Options))))
+
+	for _, name := range names {
+		if err := rollbackMigration(p, tracker, regMap, name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ Rollback completed successfully"))
+	return nil
+}
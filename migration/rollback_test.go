@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+func TestSelectRollback(t *testing.T) {
+	records := []AppliedMigration{
+		{Name: "001_users", Batch: 1},
+		{Name: "002_posts", Batch: 1},
+		{Name: "003_tags", Batch: 2},
+		{Name: "004_likes", Batch: 3},
+		{Name: "005_follows", Batch: 3},
+	}
+
+	tests := []struct {
+		name string
+		opts RunOptions
+		want []string
+	}{
+		{"last batch", RunOptions{}, []string{"005_follows", "004_likes"}},
+		{"all", RunOptions{All: true}, []string{"005_follows", "004_likes", "003_tags", "002_posts", "001_users"}},
+		{"step", RunOptions{Step: 3}, []string{"005_follows", "004_likes", "003_tags"}},
+		{"step beyond applied", RunOptions{Step: 10}, []string{"005_follows", "004_likes", "003_tags", "002_posts", "001_users"}},
+		{"batch", RunOptions{Batch: 1}, []string{"002_posts", "001_users"}},
+		{"missing batch", RunOptions{Batch: 7}, nil},
+		{"to batch", RunOptions{ToBatch: 1}, []string{"005_follows", "004_likes", "003_tags"}},
+		{"to last batch", RunOptions{ToBatch: 3}, nil},
+	}
+	if got := selectRollback(nil, RunOptions{}); len(got) != 0 {
+		t.Errorf("selectRollback(nil) = %v, want none", got)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectRollback(records, tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("selectRollback() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRollbackTarget(t *testing.T) {
+	if err := (RunOptions{Step: 2}).validateRollbackTarget(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (RunOptions{All: true, Batch: 2}).validateRollbackTarget(); err == nil {
+		t.Error("expected error for --all with --batch")
+	}
+	if err := (RunOptions{Step: -1}).validateRollbackTarget(); err == nil {
+		t.Error("expected error for negative --step")
+	}
+}
+
+func TestRunRollback_SelectedOnLegacyTable(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RunOptions
+		want []string
+	}{
+		{"step", RunOptions{Step: 1}, []string{"001_users"}},
+		{"batch", RunOptions{Batch: 1}, []string{"002_posts"}},
+		{"to batch", RunOptions{ToBatch: 1}, []string{"001_users"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newFakeDB(t)
+			db.createLegacyTable(fakeRecord{name: "001_users", batch: 1}, fakeRecord{name: "002_posts", batch: 2})
+			p := RunParams{Config: &config.Config{}, Registrations: testRegistrations(), Schema: s, Options: tt.opts, out: io.Discard}
+
+			if err := RunRollback(p); err != nil {
+				t.Fatalf("RunRollback() error: %v", err)
+			}
+			if got := db.appliedNames(); !slices.Equal(got, tt.want) {
+				t.Errorf("tracking table = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
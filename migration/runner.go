@@ -1,8 +1,12 @@
 package migration
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/Grandbusta/jone/config"
 	"github.com/Grandbusta/jone/internal/term"
@@ -11,9 +15,32 @@ import (
 
 // RunOptions holds optional flags for migration commands.
 type RunOptions struct {
-	All    bool     // For rollback --all (rollback all batches)
-	DryRun bool     // Show SQL without executing
-	Args   []string // Positional arguments
+	All     bool     // For rollback --all (rollback all batches)
+	Step    int      // For rollback --step (rollback the last N migrations)
+	Batch   int      // For rollback --batch (rollback one batch)
+	ToBatch int      // For rollback --to-batch (rollback every batch above N)
+	DryRun  bool     // Show SQL without executing
+	Repair  bool     // For validate --repair (store current checksums)
+	Args    []string // Positional arguments
+
+	// AllowMissing lets latest run when applied migrations are missing from the registry.
+	AllowMissing bool
+	// Force lets fresh run when Config.Environment is unset or production.
+	Force bool
+	// SingleTransaction runs all pending migrations of latest in one transaction.
+	SingleTransaction bool
+
+	// From, To, Down and Out select and place the script written by migrate:sql.
+	From string // Last migration already applied; the script starts after it
+	To   string // Last migration to include
+	Down bool   // Write Down migrations, most recent first; for fake, remove the record
+	Out  string // Output file ("" = stdout)
+
+	// Output is OutputText (default) or OutputJSON. With OutputJSON, list and
+	// history print JSON and runs print one JSON event per line, while
+	// human-readable progress goes to stderr.
+	Output string
+	Check  bool // For list --check (fail if any migration is pending)
 }
 
 // RunParams holds all parameters needed to run migrations.
@@ -22,20 +49,72 @@ type RunParams struct {
 	Registrations []Registration
 	Schema        *schema.Schema
 	Options       RunOptions
+	// Context cancels the run. The migration in progress is rolled back and no
+	// further migrations start. Nil means context.Background().
+	Context context.Context
+
+	out     io.Writer   // Replaces stdout for progress messages; set by Migrator
+	onEvent func(Event) // Receives every event; set by Migrator
+}
+
+// context returns p.Context, or context.Background() if it is nil.
+func (p RunParams) context() context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
+
+// newTracker creates a tracker for the configured table that honours p.Context.
+func (p RunParams) newTracker() *Tracker {
+	tracker := NewTracker(p.Schema.DB(), p.Schema.Dialect(), p.Config.Migrations.TableName).WithContext(p.context())
+	tracker.out = p.stdout()
+	return tracker
+}
+
+// missingFromRegistry returns applied migration names that have no registration,
+// in the order they were applied. This happens when a migration folder is
+// deleted or renamed after it ran.
+func missingFromRegistry(applied []string, regs []Registration) []string {
+	registered := make(map[string]bool, len(regs))
+	for _, reg := range regs {
+		registered[reg.Name] = true
+	}
+	var missing []string
+	for _, name := range applied {
+		if !registered[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
 }
 
 // RunLatest executes pending Up migrations in order using the provided schema.
 // Each migration is wrapped in a transaction.
 func RunLatest(p RunParams) error {
+	if err := p.checkSingleTransaction(); err != nil {
+		return err
+	}
+
 	// Dry-run mode: just show what would be executed
 	if p.Options.DryRun {
 		return runLatestDryRun(p)
 	}
 
-	// Create tracker
-	d := p.Schema.Dialect()
-	tracker := NewTracker(p.Schema.DB(), d, p.Config.Migrations.TableName)
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return runLatest(p, tracker)
+}
 
+// runLatest applies pending migrations in one batch. The caller holds the lock.
+func runLatest(p RunParams, tracker *Tracker) error {
 	// Ensure tracking table exists
 	if err := tracker.EnsureTable(); err != nil {
 		return err // Error already descriptive from tracker
@@ -51,6 +130,15 @@ func RunLatest(p RunParams) error {
 		appliedSet[name] = true
 	}
 
+	// Refuse to build on a history the registry doesn't know about
+	if missing := missingFromRegistry(applied, p.Registrations); len(missing) > 0 {
+		if !p.Options.AllowMissing {
+			return fmt.Errorf("%d applied migration(s) missing from the registry: %s. Restore them, or use --allow-missing to run anyway",
+				len(missing), strings.Join(missing, ", "))
+		}
+		fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Warning: %d applied migration(s) missing from the registry: %s", len(missing), strings.Join(missing, ", "))))
+	}
+
 	// Filter to pending
 	var pending []Registration
 	for _, reg := range p.Registrations {
@@ -60,10 +148,14 @@ func RunLatest(p RunParams) error {
 	}
 
 	if len(pending) == 0 {
-		fmt.Println(term.YellowText("No pending migrations"))
+		fmt.Fprintln(p.stdout(), term.YellowText("No pending migrations"))
 		return nil
 	}
 
+	if err := checkOutOfOrder(p, applied, pending); err != nil {
+		return err
+	}
+
 	// Get next batch number
 	lastBatch, err := tracker.GetLastBatch()
 	if err != nil {
@@ -71,7 +163,16 @@ func RunLatest(p RunParams) error {
 	}
 	batch := lastBatch + 1
 
-	fmt.Println(term.CyanText(fmt.Sprintf("Running %d migration(s) in batch %d...", len(pending), batch)))
+	if p.singleTransaction() {
+		fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Running %d migration(s) in batch %d in a single transaction...", len(pending), batch)))
+		if err := runInSingleTransaction(p, tracker, pending, batch); err != nil {
+			return err
+		}
+		fmt.Fprintln(p.stdout(), term.GreenText("✓ All migrations completed successfully"))
+		return nil
+	}
+
+	fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Running %d migration(s) in batch %d...", len(pending), batch)))
 
 	// Run each pending migration in a transaction
 	for _, reg := range pending {
@@ -80,30 +181,52 @@ func RunLatest(p RunParams) error {
 		}
 	}
 
-	fmt.Println(term.GreenText("✓ All migrations completed successfully"))
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ All migrations completed successfully"))
 	return nil
 }
 
-// runLatestDryRun shows what migrations would be run without executing.
+// runLatestDryRun shows the pending migrations that would be run without
+// executing them. It only queries the tracking table.
 func runLatestDryRun(p RunParams) error {
-	fmt.Println(term.YellowText("[DRY RUN]") + " Would run the following migrations:")
-	fmt.Println()
+	records, err := p.dryRunRecords()
+	if err != nil {
+		return err
+	}
 
-	for _, reg := range p.Registrations {
-		fmt.Printf("Migration: %s\n", term.GreenText(reg.Name))
-		fmt.Println("SQL:")
-		reg.Up(p.Schema) // Schema has no execer, so it prints SQL
-		fmt.Println()
+	pending := pendingRegistrations(appliedNames(records), p.Registrations)
+	if len(pending) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText("No pending migrations"))
+		return nil
+	}
+	return printDryRunUp(p, pending)
+}
+
+// printDryRunUp prints the Up SQL of regs as a dry run.
+func printDryRunUp(p RunParams, regs []Registration) error {
+	plan, err := planUp(p.Schema, regs)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Total: %d migration(s) would be applied\n", len(p.Registrations))
+	fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+" Would run the following migrations:")
+	fmt.Fprintln(p.stdout())
+
+	for _, name := range plan.Migrations {
+		fmt.Fprintf(p.stdout(), "Migration: %s\n", term.GreenText(name))
+		fmt.Fprintln(p.stdout(), "SQL:")
+		for _, stmt := range plan.MigrationStatements(name) {
+			fmt.Fprintln(p.stdout(), stmt.SQL)
+		}
+		fmt.Fprintln(p.stdout())
+	}
+
+	fmt.Fprintf(p.stdout(), "Total: %d migration(s) would be applied\n", len(plan.Migrations))
 	return nil
 }
 
 // RunList displays all migrations with their status (applied/pending).
 func RunList(p RunParams) error {
-	d := p.Schema.Dialect()
-	tracker := NewTracker(p.Schema.DB(), d, p.Config.Migrations.TableName)
+	tracker := p.newTracker()
 
 	// Ensure tracking table exists
 	if err := tracker.EnsureTable(); err != nil {
@@ -111,35 +234,23 @@ func RunList(p RunParams) error {
 	}
 
 	// Get applied migrations
-	applied, err := tracker.GetApplied()
+	records, err := tracker.GetRecords()
 	if err != nil {
 		return fmt.Errorf("getting applied migrations: %w", err)
 	}
-	appliedSet := make(map[string]bool)
-	for _, name := range applied {
-		appliedSet[name] = true
-	}
-
-	// Count stats
-	appliedCount := 0
-	pendingCount := 0
-
-	fmt.Println("\nMigrations:")
-	fmt.Println("───────────────────────────────────────────────────────")
+	report := buildListReport(p, records)
 
-	for _, reg := range p.Registrations {
-		if appliedSet[reg.Name] {
-			fmt.Printf("  %s  %s\n", term.GreenText("✓"), reg.Name)
-			appliedCount++
-		} else {
-			fmt.Printf("  %s  %s\n", term.YellowText("○"), reg.Name)
-			pendingCount++
+	if p.jsonOutput() {
+		if err := writeJSON(report); err != nil {
+			return err
 		}
+	} else {
+		writeList(p.stdout(), report)
 	}
 
-	fmt.Println("───────────────────────────────────────────────────────")
-	fmt.Printf("Total: %s, %s\n\n", term.GreenText(fmt.Sprintf("%d applied", appliedCount)), term.YellowText(fmt.Sprintf("%d pending", pendingCount)))
-
+	if p.Options.Check && report.Pending > 0 {
+		return fmt.Errorf("%d migration(s) pending", report.Pending)
+	}
 	return nil
 }
 
@@ -151,8 +262,14 @@ func RunUp(p RunParams) error {
 		return runUpDryRun(p)
 	}
 
-	d := p.Schema.Dialect()
-	tracker := NewTracker(p.Schema.DB(), d, p.Config.Migrations.TableName)
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Ensure tracking table exists
 	if err := tracker.EnsureTable(); err != nil {
@@ -192,7 +309,7 @@ func RunUp(p RunParams) error {
 			return fmt.Errorf("migration %s not found in registry", targetName)
 		}
 		if appliedSet[targetName] {
-			fmt.Println(term.YellowText(fmt.Sprintf("Migration %s already applied", targetName)))
+			fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Migration %s already applied", targetName)))
 			return nil
 		}
 		targetReg = reg
@@ -205,7 +322,7 @@ func RunUp(p RunParams) error {
 			}
 		}
 		if len(pending) == 0 {
-			fmt.Println(term.YellowText("No pending migrations"))
+			fmt.Fprintln(p.stdout(), term.YellowText("No pending migrations"))
 			return nil
 		}
 		targetReg = pending[0]
@@ -216,30 +333,45 @@ func RunUp(p RunParams) error {
 		return err
 	}
 
-	fmt.Println(term.GreenText("✓ Migration completed successfully"))
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ Migration completed successfully"))
 	return nil
 }
 
 // runMigration runs a single migration in a transaction.
 func runMigration(p RunParams, tracker *Tracker, reg Registration, batch int) error {
-	tx, err := p.Schema.BeginTx()
+	if reg.Options.DisableTransaction {
+		return runMigrationWithoutTx(p, tracker, reg, batch)
+	}
+
+	s := p.Schema.WithContext(p.context())
+	tx, err := s.BeginTx()
 	if err != nil {
 		return fmt.Errorf("failed to start transaction for '%s': %w", reg.Name, err)
 	}
+	defer tx.Rollback() // No-op after a successful Commit
 
-	txSchema := p.Schema.WithTx(tx)
-	reg.Up(txSchema)
-
-	if err := tracker.RecordMigrationTx(tx, reg.Name, batch); err != nil {
+	start := time.Now()
+	txSchema := s.WithTx(tx)
+	if err := reg.up(txSchema); err != nil {
 		tx.Rollback()
+		p.logFailure(tracker, reg.Name, directionUp, batch, time.Since(start), err)
+		return fmt.Errorf("migration '%s' failed: %w", reg.Name, err)
+	}
+	duration := time.Since(start)
+
+	if err := tracker.RecordMigrationTx(tx, reg.Name, batch, checksumOrEmpty(reg, p.Schema), duration); err != nil {
 		return fmt.Errorf("failed to record migration '%s': %w", reg.Name, err)
 	}
+	if err := tracker.LogTx(tx, reg.Name, directionUp, batch, duration, nil); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit migration '%s': %w", reg.Name, err)
 	}
 
-	fmt.Println(term.GreenText(fmt.Sprintf("  ✓ Migrated: %s", reg.Name)))
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Migrated: %s", reg.Name)))
+	p.emit(EventMigrated, reg.Name, directionUp, batch, duration, nil)
 	return nil
 }
 
@@ -251,8 +383,19 @@ func RunDown(p RunParams) error {
 		return runDownDryRun(p)
 	}
 
-	d := p.Schema.Dialect()
-	tracker := NewTracker(p.Schema.DB(), d, p.Config.Migrations.TableName)
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Ensure tracking table exists and has the history table
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
 
 	applied, err := tracker.GetApplied()
 	if err != nil {
@@ -260,7 +403,7 @@ func RunDown(p RunParams) error {
 	}
 
 	if len(applied) == 0 {
-		fmt.Println(term.YellowText("No migrations to rollback"))
+		fmt.Fprintln(p.stdout(), term.YellowText("No migrations to rollback"))
 		return nil
 	}
 
@@ -289,20 +432,35 @@ func RunDown(p RunParams) error {
 		return err
 	}
 
-	fmt.Println(term.GreenText("✓ Rollback completed successfully"))
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ Rollback completed successfully"))
 	return nil
 }
 
-// RunRollback rolls back the last batch of migrations (or all if Options.All is true).
-// Each migration is wrapped in a transaction.
+// RunRollback rolls back the last batch of migrations, or the migrations selected
+// by Options.All, Step, Batch or ToBatch. Each migration is wrapped in a transaction.
 func RunRollback(p RunParams) error {
+	if err := p.Options.validateRollbackTarget(); err != nil {
+		return err
+	}
+
 	// Dry-run mode
 	if p.Options.DryRun {
 		return runRollbackDryRun(p)
 	}
 
-	d := p.Schema.Dialect()
-	tracker := NewTracker(p.Schema.DB(), d, p.Config.Migrations.TableName)
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Ensure tracking table exists and has the history table
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
 
 	// Build map of registrations for lookup
 	regMap := make(map[string]Registration)
@@ -315,6 +473,10 @@ func RunRollback(p RunParams) error {
 		return rollbackAll(p, tracker, regMap)
 	}
 
+	if p.Options.rollbackSelectsByRecord() {
+		return rollbackSelected(p, tracker, regMap)
+	}
+
 	// Rollback last batch only
 	return rollbackLastBatch(p, tracker, regMap)
 }
@@ -326,7 +488,7 @@ func rollbackLastBatch(p RunParams, tracker *Tracker, regMap map[string]Registra
 	}
 
 	if lastBatch == 0 {
-		fmt.Println(term.YellowText("Nothing to rollback."))
+		fmt.Fprintln(p.stdout(), term.YellowText("Nothing to rollback."))
 		return nil
 	}
 
@@ -336,11 +498,11 @@ func rollbackLastBatch(p RunParams, tracker *Tracker, regMap map[string]Registra
 	}
 
 	if len(batchMigrations) == 0 {
-		fmt.Println(term.YellowText("Nothing to rollback."))
+		fmt.Fprintln(p.stdout(), term.YellowText("Nothing to rollback."))
 		return nil
 	}
 
-	fmt.Println(term.CyanText(fmt.Sprintf("Rolling back %d migration(s) from batch %d...", len(batchMigrations), lastBatch)))
+	fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Rolling back %d migration(s) from batch %d...", len(batchMigrations), lastBatch)))
 
 	for _, name := range batchMigrations {
 		if err := rollbackMigration(p, tracker, regMap, name); err != nil {
@@ -348,7 +510,7 @@ func rollbackLastBatch(p RunParams, tracker *Tracker, regMap map[string]Registra
 		}
 	}
 
-	fmt.Println(term.GreenText("✓ Rollback completed successfully"))
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ Rollback completed successfully"))
 	return nil
 }
 
@@ -359,11 +521,11 @@ func rollbackAll(p RunParams, tracker *Tracker, regMap map[string]Registration)
 	}
 
 	if len(applied) == 0 {
-		fmt.Println(term.YellowText("Nothing to rollback."))
+		fmt.Fprintln(p.stdout(), term.YellowText("Nothing to rollback."))
 		return nil
 	}
 
-	fmt.Println(term.CyanText(fmt.Sprintf("Rolling back all %d migration(s)...", len(applied))))
+	fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Rolling back all %d migration(s)...", len(applied))))
 
 	// Roll back in reverse order
 	for i := len(applied) - 1; i >= 0; i-- {
@@ -372,7 +534,7 @@ func rollbackAll(p RunParams, tracker *Tracker, regMap map[string]Registration)
 		}
 	}
 
-	fmt.Println(term.GreenText("✓ Rollback completed successfully"))
+	fmt.Fprintln(p.stdout(), term.GreenText("✓ Rollback completed successfully"))
 	return nil
 }
 
@@ -381,130 +543,185 @@ func rollbackMigration(p RunParams, tracker *Tracker, regMap map[string]Registra
 	if !ok {
 		return fmt.Errorf("migration '%s' not found in registry. Was it deleted or renamed?", name)
 	}
+	if reg.Options.DisableTransaction {
+		return rollbackMigrationWithoutTx(p, tracker, reg)
+	}
 
-	tx, err := p.Schema.BeginTx()
+	s := p.Schema.WithContext(p.context())
+	tx, err := s.BeginTx()
 	if err != nil {
 		return fmt.Errorf("failed to start transaction for rollback '%s': %w", name, err)
 	}
+	defer tx.Rollback() // No-op after a successful Commit
 
-	txSchema := p.Schema.WithTx(tx)
-	reg.Down(txSchema)
+	start := time.Now()
+	txSchema := s.WithTx(tx)
+	if err := reg.down(txSchema); err != nil {
+		tx.Rollback()
+		p.logFailure(tracker, name, directionDown, 0, time.Since(start), err)
+		return fmt.Errorf("rollback of '%s' failed: %w", name, err)
+	}
+	duration := time.Since(start)
 
 	if err := tracker.RemoveMigrationTx(tx, name); err != nil {
-		tx.Rollback()
 		return fmt.Errorf("failed to remove migration record '%s': %w", name, err)
 	}
+	if err := tracker.LogTx(tx, name, directionDown, 0, duration, nil); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit rollback '%s': %w", name, err)
 	}
 
-	fmt.Println(term.GreenText(fmt.Sprintf("  ✓ Rolled back: %s", name)))
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Rolled back: %s", name)))
+	p.emit(EventRolledBack, name, directionDown, 0, duration, nil)
 	return nil
 }
 
+// logFailure appends a failed run to the history table and reports it. The
+// run's transaction must already be rolled back. Logging is best effort, so its
+// error is only printed and never hides runErr; it runs even if the run was
+// cancelled.
+func (p RunParams) logFailure(tracker *Tracker, name, direction string, batch int, duration time.Duration, runErr error) {
+	p.emit(EventFailed, name, direction, batch, duration, runErr)
+	tracker = tracker.WithContext(context.WithoutCancel(tracker.context()))
+	if err := tracker.Log(name, direction, batch, duration, runErr); err != nil {
+		fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("  Warning: %v", err)))
+	}
+}
+
+// dryRunRecords returns the applied migrations for a dry run. It only queries
+// the tracking table: it isn't created when it doesn't exist yet, nor upgraded
+// when an older version of jone created it.
+func (p RunParams) dryRunRecords() ([]AppliedMigration, error) {
+	if p.Schema.DB() == nil {
+		return nil, fmt.Errorf("dry run needs a database connection to read applied migrations")
+	}
+	tracker := p.newTracker()
+	exists, err := tracker.Exists()
+	if err != nil || !exists {
+		return nil, err
+	}
+	return tracker.readRecords()
+}
+
+// appliedNames returns the names of records, in applied order.
+func appliedNames(records []AppliedMigration) []string {
+	names := make([]string, len(records))
+	for i, rec := range records {
+		names[i] = rec.Name
+	}
+	return names
+}
+
 // runUpDryRun shows what migration would be run without executing.
 func runUpDryRun(p RunParams) error {
-	// Build map of registrations
-	regMap := make(map[string]Registration)
-	for _, reg := range p.Registrations {
-		regMap[reg.Name] = reg
+	records, err := p.dryRunRecords()
+	if err != nil {
+		return err
 	}
+	applied := appliedNames(records)
 
 	var targetReg Registration
 	if len(p.Options.Args) > 0 {
 		// Specific migration
 		targetName := p.Options.Args[0]
-		reg, ok := regMap[targetName]
+		reg, ok := p.registrationMap()[targetName]
 		if !ok {
 			return fmt.Errorf("migration %s not found in registry", targetName)
 		}
+		if slices.Contains(applied, targetName) {
+			fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Migration %s already applied", targetName)))
+			return nil
+		}
 		targetReg = reg
 	} else {
-		// First pending (just use first registered for dry-run)
-		if len(p.Registrations) == 0 {
-			fmt.Println(term.YellowText("No migrations registered"))
+		// Next pending migration
+		idx := slices.IndexFunc(p.Registrations, func(r Registration) bool { return !slices.Contains(applied, r.Name) })
+		if idx < 0 {
+			fmt.Fprintln(p.stdout(), term.YellowText("No pending migrations"))
 			return nil
 		}
-		targetReg = p.Registrations[0]
+		targetReg = p.Registrations[idx]
 	}
 
-	fmt.Println(term.YellowText("[DRY RUN]") + " Would run migration:")
-	fmt.Println()
-	fmt.Printf("Migration: %s\n", term.GreenText(targetReg.Name))
-	fmt.Println("SQL:")
-	targetReg.Up(p.Schema)
-	fmt.Println()
+	fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+" Would run migration:")
+	fmt.Fprintln(p.stdout())
+	fmt.Fprintf(p.stdout(), "Migration: %s\n", term.GreenText(targetReg.Name))
+	fmt.Fprintln(p.stdout(), "SQL:")
+	if err := p.printRecorded(targetReg.up); err != nil {
+		return fmt.Errorf("migration '%s' failed: %w", targetReg.Name, err)
+	}
+	fmt.Fprintln(p.stdout())
 	return nil
 }
 
 // runDownDryRun shows what migration would be rolled back without executing.
 func runDownDryRun(p RunParams) error {
-	// Build map of registrations
-	regMap := make(map[string]Registration)
-	for _, reg := range p.Registrations {
-		regMap[reg.Name] = reg
+	records, err := p.dryRunRecords()
+	if err != nil {
+		return err
 	}
+	applied := appliedNames(records)
 
 	var targetName string
 	if len(p.Options.Args) > 0 {
 		targetName = p.Options.Args[0]
-	} else if len(p.Registrations) > 0 {
-		// In dry-run we can't check DB, so use last registered
-		targetName = p.Registrations[len(p.Registrations)-1].Name
+		if !slices.Contains(applied, targetName) {
+			return fmt.Errorf("migration %s not found in applied migrations", targetName)
+		}
+	} else if len(applied) > 0 {
+		targetName = applied[len(applied)-1]
 	} else {
-		fmt.Println(term.YellowText("No migrations registered"))
+		fmt.Fprintln(p.stdout(), term.YellowText("No migrations to rollback"))
 		return nil
 	}
 
-	reg, ok := regMap[targetName]
+	reg, ok := p.registrationMap()[targetName]
 	if !ok {
-		return fmt.Errorf("migration %s not found in registry", targetName)
+		return fmt.Errorf("migration '%s' not found in registry. Was it deleted or renamed?", targetName)
 	}
 
-	fmt.Println(term.YellowText("[DRY RUN]") + " Would rollback migration:")
-	fmt.Println()
-	fmt.Printf("Migration: %s\n", term.GreenText(reg.Name))
-	fmt.Println("SQL:")
-	reg.Down(p.Schema)
-	fmt.Println()
+	fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+" Would rollback migration:")
+	fmt.Fprintln(p.stdout())
+	fmt.Fprintf(p.stdout(), "Migration: %s\n", term.GreenText(reg.Name))
+	fmt.Fprintln(p.stdout(), "SQL:")
+	if err := p.printRecorded(reg.down); err != nil {
+		return fmt.Errorf("rollback of '%s' failed: %w", reg.Name, err)
+	}
+	fmt.Fprintln(p.stdout())
 	return nil
 }
 
 // runRollbackDryRun shows what migrations would be rolled back without executing.
 func runRollbackDryRun(p RunParams) error {
-	// Build map of registrations
-	regMap := make(map[string]Registration)
-	for _, reg := range p.Registrations {
-		regMap[reg.Name] = reg
+	records, err := p.dryRunRecords()
+	if err != nil {
+		return err
 	}
 
-	if len(p.Registrations) == 0 {
-		fmt.Println(term.YellowText("No migrations registered"))
+	names := selectRollback(records, p.Options)
+	if len(names) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText("Nothing to rollback."))
 		return nil
 	}
 
-	fmt.Println(term.YellowText("[DRY RUN]") + " Would rollback migrations:")
-	fmt.Println()
-
-	if p.Options.All {
-		// All migrations in reverse order
-		for i := len(p.Registrations) - 1; i >= 0; i-- {
-			reg := p.Registrations[i]
-			fmt.Printf("Migration: %s\n", term.GreenText(reg.Name))
-			fmt.Println("SQL:")
-			reg.Down(p.Schema)
-			fmt.Println()
+	regMap := p.registrationMap()
+	fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+" Would rollback migrations:")
+	fmt.Fprintln(p.stdout())
+	for _, name := range names {
+		reg, ok := regMap[name]
+		if !ok {
+			return fmt.Errorf("migration '%s' not found in registry. Was it deleted or renamed?", name)
 		}
-		fmt.Printf("Total: %d migration(s) would be rolled back\n", len(p.Registrations))
-	} else {
-		// Just the last one
-		reg := p.Registrations[len(p.Registrations)-1]
-		fmt.Printf("Migration: %s\n", term.GreenText(reg.Name))
-		fmt.Println("SQL:")
-		reg.Down(p.Schema)
-		fmt.Println()
+		fmt.Fprintf(p.stdout(), "Migration: %s\n", term.GreenText(name))
+		fmt.Fprintln(p.stdout(), "SQL:")
+		if err := p.printRecorded(reg.down); err != nil {
+			return fmt.Errorf("rollback of '%s' failed: %w", name, err)
+		}
+		fmt.Fprintln(p.stdout())
 	}
-
+	fmt.Fprintf(p.stdout(), "Total: %d migration(s) would be rolled back\n", len(names))
 	return nil
 }
@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+func TestMissingFromRegistry(t *testing.T) {
+	regs := []Registration{{Name: "001_users"}, {Name: "003_posts"}}
+	applied := []string{"001_users", "002_renamed", "003_posts", "004_deleted"}
+
+	got := missingFromRegistry(applied, regs)
+	want := []string{"002_renamed", "004_deleted"}
+	if !slices.Equal(got, want) {
+		t.Errorf("missingFromRegistry() = %v, want %v", got, want)
+	}
+
+	if got := missingFromRegistry([]string{"001_users"}, regs); len(got) != 0 {
+		t.Errorf("missingFromRegistry() = %v, want none", got)
+	}
+}
+
+func TestDryRunRecords_LegacyTable(t *testing.T) {
+	s, db := newFakeDB(t)
+	db.createLegacyTable(fakeRecord{name: "001_users", batch: 1}, fakeRecord{name: "002_posts", batch: 2})
+	p := RunParams{Config: &config.Config{}, Schema: s}
+
+	records, err := p.dryRunRecords()
+	if err != nil {
+		t.Fatalf("dryRunRecords() error: %v", err)
+	}
+	want := []AppliedMigration{{Name: "001_users", Batch: 1}, {Name: "002_posts", Batch: 2}}
+	if !slices.Equal(records, want) {
+		t.Errorf("records = %+v, want %+v", records, want)
+	}
+	if len(db.state.missing) != len(trackingColumns) || len(db.state.executed) != 0 {
+		t.Errorf("dry run changed the database: missing columns %v, executed %q", db.state.missing, db.state.executed)
+	}
+}
+
+func TestDryRunRecords_NoTable(t *testing.T) {
+	s, db := newFakeDB(t)
+	p := RunParams{Config: &config.Config{}, Schema: s}
+
+	records, err := p.dryRunRecords()
+	if err != nil || records != nil {
+		t.Errorf("dryRunRecords() = %v, %v; want no records", records, err)
+	}
+	if db.state.tables["jone_migrations"] {
+		t.Error("dry run created the tracking table")
+	}
+}
+
+func TestRunRollback_LegacyTable(t *testing.T) {
+	s, db := newFakeDB(t)
+	db.createLegacyTable(fakeRecord{name: "001_users", batch: 1}, fakeRecord{name: "002_posts", batch: 2})
+	p := RunParams{Config: &config.Config{}, Registrations: testRegistrations(), Schema: s, out: io.Discard}
+
+	if err := RunRollback(p); err != nil {
+		t.Fatalf("RunRollback() error: %v", err)
+	}
+	if got := db.appliedNames(); !slices.Equal(got, []string{"001_users"}) {
+		t.Errorf("tracking table = %v", got)
+	}
+	if len(db.state.missing) != 0 || !db.state.tables["jone_migrations_log"] {
+		t.Errorf("tracking table not upgraded: missing columns %v", db.state.missing)
+	}
+	if want := []fakeLogEntry{{"002_posts", directionDown, statusSuccess}}; !slices.Equal(db.state.history, want) {
+		t.Errorf("history = %+v, want %+v", db.state.history, want)
+	}
+
+	if err := RunDown(p); err != nil {
+		t.Fatalf("RunDown() error: %v", err)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want empty", got)
+	}
+}
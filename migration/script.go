@@ -0,0 +1,132 @@
+package migration
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/Grandbusta/jone/schema"
+)
+
+// selectScript returns the registrations after from up to and including to.
+// Empty bounds select from the first or through the last registration.
+func selectScript(regs []Registration, from, to string) ([]Registration, error) {
+	start, end := 0, len(regs)
+	if from != "" {
+		idx := slices.IndexFunc(regs, func(r Registration) bool { return r.Name == from })
+		if idx < 0 {
+			return nil, fmt.Errorf("migration %s not found in registry", from)
+		}
+		start = idx + 1
+	}
+	if to != "" {
+		idx := slices.IndexFunc(regs, func(r Registration) bool { return r.Name == to })
+		if idx < 0 {
+			return nil, fmt.Errorf("migration %s not found in registry", to)
+		}
+		end = idx + 1
+	}
+	if start > end {
+		return nil, fmt.Errorf("--from %s comes after --to %s", from, to)
+	}
+	return regs[start:end], nil
+}
+
+// RunSQL writes the migrations selected by Options.From and Options.To as a
+// standalone SQL script, to Options.Out or stdout. Each migration is wrapped in
+// a transaction (unless it sets MigrationOptions.DisableTransaction) together
+// with the tracking-table statement that records (or, with Options.Down,
+// removes) it, so running the script by hand leaves the tracking table
+// consistent. No connection is needed.
+func RunSQL(p RunParams) error {
+	regs, err := selectScript(p.Registrations, p.Options.From, p.Options.To)
+	if err != nil {
+		return err
+	}
+
+	script, err := renderScript(p, regs)
+	if err != nil {
+		return err
+	}
+
+	if p.Options.Out == "" {
+		_, err := io.WriteString(os.Stdout, script)
+		return err
+	}
+	if err := os.WriteFile(p.Options.Out, []byte(script), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", p.Options.Out, err)
+	}
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ Wrote %d migration(s) to %s", len(regs), p.Options.Out)))
+	return nil
+}
+
+// renderScript renders regs as a SQL script for the schema's dialect.
+func renderScript(p RunParams, regs []Registration) (string, error) {
+	d := p.Schema.Dialect()
+	scripter, ok := d.(dialect.Scripter)
+	if !ok {
+		return "", fmt.Errorf("migrate:sql is not supported by the %s dialect", d.Name())
+	}
+	table := p.newTracker().tableName
+
+	direction := "up"
+	if p.Options.Down {
+		direction = "down"
+		regs = slices.Clone(regs)
+		slices.Reverse(regs)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Generated by jone migrate:sql for %s\n", d.Name())
+	fmt.Fprintf(&b, "-- %d migration(s), %s\n", len(regs), direction)
+	if !p.Options.Down {
+		fmt.Fprintln(&b, "-- The tracking table needs the checksum column. If it was created by an older")
+		fmt.Fprintln(&b, "-- jone, run any jone command against the database first to upgrade it.")
+		fmt.Fprintf(&b, "\n%s\n", scripter.TerminateStatement(d.CreateMigrationsTableSQL(table)))
+	}
+
+	for i, reg := range regs {
+		rec := schema.NewRecorder()
+		var track string
+		var trackArgs []any
+		if p.Options.Down {
+			if err := reg.down(p.Schema.WithRecorder(rec)); err != nil {
+				return "", fmt.Errorf("rollback of '%s' failed: %w", reg.Name, err)
+			}
+			track, trackArgs = d.DeleteMigrationSQL(table), []any{reg.Name}
+		} else {
+			if err := reg.up(p.Schema.WithRecorder(rec)); err != nil {
+				return "", fmt.Errorf("migration '%s' failed: %w", reg.Name, err)
+			}
+			offset := 0 // Every migration in the script shares one batch
+			if i == 0 {
+				offset = 1
+			}
+			track = scripter.InsertMigrationScriptSQL(table)
+			trackArgs = []any{reg.Name, offset, nullable(checksumOrEmpty(reg, p.Schema))}
+		}
+
+		fmt.Fprintf(&b, "\n-- Migration: %s\n", reg.Name)
+		if reg.Options.DisableTransaction {
+			fmt.Fprintln(&b, "-- Runs without a transaction: if a statement fails, the earlier ones stay applied")
+		} else {
+			fmt.Fprintln(&b, scripter.TerminateStatement(scripter.BeginTransactionSQL()))
+		}
+		stmts := append(rec.Statements(), schema.Statement{SQL: track, Args: trackArgs})
+		for _, stmt := range stmts {
+			query, err := scripter.InlineArgs(stmt.SQL, stmt.Args)
+			if err != nil {
+				return "", fmt.Errorf("migration '%s': %w", reg.Name, err)
+			}
+			fmt.Fprintln(&b, scripter.TerminateStatement(query))
+		}
+		if !reg.Options.DisableTransaction {
+			fmt.Fprintln(&b, scripter.TerminateStatement(scripter.CommitTransactionSQL()))
+		}
+	}
+	return b.String(), nil
+}
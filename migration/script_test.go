@@ -0,0 +1,153 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/schema"
+)
+
+func scriptRegistrations() []Registration {
+	return []Registration{
+		{
+			Name: "001_users",
+			Up: func(s *schema.Schema) {
+				s.CreateTable("users", func(t *schema.Table) { t.Increments("id") })
+			},
+			Down: func(s *schema.Schema) { s.DropTable("users") },
+		},
+		{
+			Name: "002_seed",
+			Up:   func(s *schema.Schema) { s.Raw("INSERT INTO users (id) VALUES ($1)", 7) },
+			Down: func(s *schema.Schema) { s.Raw("DELETE FROM users WHERE id = $1", 7) },
+		},
+		{Name: "003_posts"},
+	}
+}
+
+func TestSelectScript(t *testing.T) {
+	regs := scriptRegistrations()
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"", "", 3},
+		{"001_users", "", 2},
+		{"", "002_seed", 2},
+		{"001_users", "002_seed", 1},
+		{"002_seed", "002_seed", 0},
+	}
+	for _, tt := range tests {
+		got, err := selectScript(regs, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("selectScript(%q, %q) error: %v", tt.from, tt.to, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("selectScript(%q, %q) = %d migration(s), want %d", tt.from, tt.to, len(got), tt.want)
+		}
+	}
+
+	if _, err := selectScript(regs, "003_posts", "001_users"); err == nil {
+		t.Error("expected error when --from comes after --to")
+	}
+	if _, err := selectScript(regs, "999_nope", ""); err == nil {
+		t.Error("expected error for an unknown migration")
+	}
+}
+
+func TestRenderScript(t *testing.T) {
+	p := RunParams{
+		Config:        &config.Config{},
+		Schema:        newTestSchema(t),
+		Registrations: scriptRegistrations(),
+	}
+
+	up, err := renderScript(p, p.Registrations[:2])
+	if err != nil {
+		t.Fatalf("renderScript() error: %v", err)
+	}
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "public"."jone_migrations"`,
+		"BEGIN;",
+		"INSERT INTO users (id) VALUES (7);",
+		`SELECT '001_users', COALESCE(MAX(batch), 0) + 1,`,
+		`SELECT '002_seed', COALESCE(MAX(batch), 0) + 0,`,
+		"COMMIT;",
+	} {
+		if !strings.Contains(up, want) {
+			t.Errorf("up script missing %q:\n%s", want, up)
+		}
+	}
+
+	p.Options.Down = true
+	down, err := renderScript(p, p.Registrations[:2])
+	if err != nil {
+		t.Fatalf("renderScript() error: %v", err)
+	}
+	seed := strings.Index(down, "DELETE FROM users WHERE id = 7;")
+	users := strings.Index(down, `DELETE FROM "public"."jone_migrations" WHERE name = '001_users';`)
+	if seed < 0 || users < 0 || seed > users {
+		t.Errorf("down script should roll back 002_seed before 001_users:\n%s", down)
+	}
+}
+
+func TestRenderScript_DisableTransaction(t *testing.T) {
+	regs := scriptRegistrations()
+	regs[1].Options.DisableTransaction = true
+	p := RunParams{Config: &config.Config{}, Schema: newTestSchema(t), Registrations: regs}
+
+	script, err := renderScript(p, regs[1:2])
+	if err != nil {
+		t.Fatalf("renderScript() error: %v", err)
+	}
+	if strings.Contains(script, "BEGIN;") || strings.Contains(script, "COMMIT;") {
+		t.Errorf("script wraps a DisableTransaction migration in a transaction:\n%s", script)
+	}
+}
+
+func TestRenderScript_ClientSyntax(t *testing.T) {
+	tests := []struct {
+		client string
+		body   string
+		want   []string
+	}{
+		{
+			"mssql", "CREATE PROCEDURE touch AS BEGIN UPDATE users SET n = 1; SELECT 1; END",
+			[]string{
+				"BEGIN TRANSACTION;\nGO\n",
+				"CREATE PROCEDURE touch AS BEGIN UPDATE users SET n = 1; SELECT 1; END\nGO\n",
+				"COMMIT TRANSACTION;\nGO\n",
+			},
+		},
+		{
+			"mysql", "CREATE PROCEDURE touch() BEGIN UPDATE users SET n = 1; SELECT 1; END",
+			[]string{
+				"START TRANSACTION;\n",
+				"DELIMITER //\nCREATE PROCEDURE touch() BEGIN UPDATE users SET n = 1; SELECT 1; END//\nDELIMITER ;\n",
+				"COMMIT;\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			s, err := schema.New(&config.Config{Client: tt.client})
+			if err != nil {
+				t.Fatalf("schema.New() error: %v", err)
+			}
+			regs := []Registration{{Name: "001_touch", Up: func(s *schema.Schema) { s.Raw(tt.body) }}}
+			p := RunParams{Config: &config.Config{}, Schema: s, Registrations: regs}
+
+			script, err := renderScript(p, regs)
+			if err != nil {
+				t.Fatalf("renderScript() error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script missing %q:\n%s", want, script)
+				}
+			}
+		})
+	}
+}
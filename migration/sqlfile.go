@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/schema"
+)
+
+// SQLFile returns an Up or Down function that runs the SQL script name from
+// fsys, one statement at a time. The generated registry uses it for
+// migrations written as up.sql and down.sql.
+func SQLFile(fsys fs.FS, name string) func(*schema.Schema) error {
+	return func(s *schema.Schema) error {
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		for _, stmt := range splitScript(s.Dialect(), string(script)) {
+			s.Raw(stmt)
+		}
+		return nil
+	}
+}
+
+// splitScript splits script into statements for d. Dialects that can't split
+// scripts get it back as a single statement.
+func splitScript(d dialect.Dialect, script string) []string {
+	if splitter, ok := d.(dialect.StatementSplitter); ok {
+		return splitter.SplitStatements(script)
+	}
+	return []string{script}
+}
@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Grandbusta/jone/schema"
+)
+
+func TestSQLFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"up.sql": {Data: []byte("CREATE TABLE users (id int);\n-- seed\nINSERT INTO users VALUES (1);\n")},
+	}
+	rec := schema.NewRecorder()
+	s := newTestSchema(t).WithRecorder(rec)
+
+	if err := SQLFile(fsys, "up.sql")(s); err != nil {
+		t.Fatalf("SQLFile() error: %v", err)
+	}
+	stmts := rec.Statements()
+	if len(stmts) != 2 || stmts[0].SQL != "CREATE TABLE users (id int)" || stmts[1].SQL != "-- seed\nINSERT INTO users VALUES (1)" {
+		t.Errorf("statements = %+v", stmts)
+	}
+
+	if err := SQLFile(fsys, "down.sql")(s); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestSQLMigrationWithoutDown(t *testing.T) {
+	fsys := fstest.MapFS{"up.sql": {Data: []byte("CREATE TABLE users (id int);")}}
+	reg := Registration{Name: "20260101000000_create_users", UpE: SQLFile(fsys, "up.sql")}
+	s := newTestSchema(t)
+
+	if _, err := Checksum(reg, s); err != nil {
+		t.Errorf("Checksum() error: %v", err)
+	}
+	err := reg.down(s.WithRecorder(schema.NewRecorder()))
+	if err == nil || !strings.Contains(err.Error(), "has no down migration") {
+		t.Errorf("down() error = %v, want a missing down migration error", err)
+	}
+}
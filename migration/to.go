@@ -0,0 +1,171 @@
+package migration
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Grandbusta/jone/internal/term"
+	"github.com/Grandbusta/jone/schema"
+)
+
+// toPlan is the work needed to move the database to a target migration.
+type toPlan struct {
+	Rollback []string       // Applied migrations after the target, most recent first
+	Apply    []Registration // Pending migrations up to and including the target, in order
+}
+
+// planTo works out how to reach target: every registered migration up to and
+// including it is applied and every one after it is rolled back. Applied
+// migrations missing from the registry are left alone.
+func planTo(target string, applied []string, regs []Registration) (toPlan, error) {
+	targetIdx := slices.IndexFunc(regs, func(r Registration) bool { return r.Name == target })
+	if targetIdx < 0 {
+		return toPlan{}, fmt.Errorf("migration %s not found in registry", target)
+	}
+
+	position := make(map[string]int, len(regs))
+	for i, reg := range regs {
+		position[reg.Name] = i
+	}
+
+	var plan toPlan
+	for i := len(applied) - 1; i >= 0; i-- {
+		if idx, ok := position[applied[i]]; ok && idx > targetIdx {
+			plan.Rollback = append(plan.Rollback, applied[i])
+		}
+	}
+
+	for _, reg := range regs[:targetIdx+1] {
+		if !slices.Contains(applied, reg.Name) {
+			plan.Apply = append(plan.Apply, reg)
+		}
+	}
+	return plan, nil
+}
+
+// RunTo moves the database to the migration named in Args[0], rolling back
+// migrations after it and applying pending ones up to it, as needed.
+// Each migration is wrapped in a transaction.
+func RunTo(p RunParams) error {
+	if len(p.Options.Args) == 0 {
+		return fmt.Errorf("target migration name is required")
+	}
+	target := p.Options.Args[0]
+
+	if p.Options.DryRun {
+		return runToDryRun(p, target)
+	}
+
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+
+	applied, err := tracker.GetApplied()
+	if err != nil {
+		return err
+	}
+
+	plan, err := planTo(target, applied, p.Registrations)
+	if err != nil {
+		return err
+	}
+	if len(plan.Rollback) == 0 && len(plan.Apply) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("Already at %s", target)))
+		return nil
+	}
+
+	regMap := make(map[string]Registration)
+	for _, reg := range p.Registrations {
+		regMap[reg.Name] = reg
+	}
+
+	if len(plan.Rollback) > 0 {
+		fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Rolling back %d migration(s)...", len(plan.Rollback))))
+		for _, name := range plan.Rollback {
+			if err := rollbackMigration(p, tracker, regMap, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(plan.Apply) > 0 {
+		lastBatch, err := tracker.GetLastBatch()
+		if err != nil {
+			return err
+		}
+		batch := lastBatch + 1
+
+		fmt.Fprintln(p.stdout(), term.CyanText(fmt.Sprintf("Running %d migration(s) in batch %d...", len(plan.Apply), batch)))
+		for _, reg := range plan.Apply {
+			if err := runMigration(p, tracker, reg, batch); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ Database is at %s", target)))
+	return nil
+}
+
+// runToDryRun shows the exact plan for RunTo without executing it.
+func runToDryRun(p RunParams, target string) error {
+	records, err := p.dryRunRecords()
+	if err != nil {
+		return err
+	}
+	applied := appliedNames(records)
+
+	plan, err := planTo(target, applied, p.Registrations)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(p.stdout(), term.YellowText("[DRY RUN]")+fmt.Sprintf(" Would move the database to %s:", target))
+	fmt.Fprintln(p.stdout())
+	if len(plan.Rollback) == 0 && len(plan.Apply) == 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText("Already at target, nothing to do"))
+		return nil
+	}
+
+	regMap := p.registrationMap()
+	for _, name := range plan.Rollback {
+		fmt.Fprintf(p.stdout(), "Rollback: %s\n", term.YellowText(name))
+		fmt.Fprintln(p.stdout(), "SQL:")
+		if err := p.printRecorded(regMap[name].down); err != nil {
+			return fmt.Errorf("rollback of '%s' failed: %w", name, err)
+		}
+		fmt.Fprintln(p.stdout())
+	}
+	for _, reg := range plan.Apply {
+		fmt.Fprintf(p.stdout(), "Migrate: %s\n", term.GreenText(reg.Name))
+		fmt.Fprintln(p.stdout(), "SQL:")
+		if err := p.printRecorded(reg.up); err != nil {
+			return fmt.Errorf("migration '%s' failed: %w", reg.Name, err)
+		}
+		fmt.Fprintln(p.stdout())
+	}
+
+	fmt.Fprintf(p.stdout(), "Total: %d to roll back, %d to apply\n", len(plan.Rollback), len(plan.Apply))
+	return nil
+}
+
+// printRecorded runs fn against a recording copy of p.Schema and prints the statements.
+func (p RunParams) printRecorded(fn func(*schema.Schema) error) error {
+	rec := schema.NewRecorder()
+	if err := fn(p.Schema.WithRecorder(rec)); err != nil {
+		return err
+	}
+	for _, stmt := range rec.Statements() {
+		fmt.Fprintln(p.stdout(), stmt.SQL)
+	}
+	return nil
+}
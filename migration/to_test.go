@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"slices"
+	"testing"
+)
+
+func regNames(regs []Registration) []string {
+	names := make([]string, len(regs))
+	for i, reg := range regs {
+		names[i] = reg.Name
+	}
+	return names
+}
+
+func TestPlanTo(t *testing.T) {
+	regs := []Registration{{Name: "001"}, {Name: "002"}, {Name: "003"}, {Name: "004"}}
+
+	tests := []struct {
+		name         string
+		target       string
+		applied      []string
+		wantRollback []string
+		wantApply    []string
+	}{
+		{"forward", "003", []string{"001"}, nil, []string{"002", "003"}},
+		{"backward", "001", []string{"001", "002", "003"}, []string{"003", "002"}, nil},
+		{"already there", "002", []string{"001", "002"}, nil, nil},
+		{"both directions", "002", []string{"001", "004"}, []string{"004"}, []string{"002"}},
+		{"rollback in applied order", "001", []string{"001", "003", "002"}, []string{"002", "003"}, nil},
+		{"orphans left alone", "001", []string{"001", "gone", "002"}, []string{"002"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planTo(tt.target, tt.applied, regs)
+			if err != nil {
+				t.Fatalf("planTo() error: %v", err)
+			}
+			if !slices.Equal(plan.Rollback, tt.wantRollback) {
+				t.Errorf("Rollback = %v, want %v", plan.Rollback, tt.wantRollback)
+			}
+			if got := regNames(plan.Apply); !slices.Equal(got, tt.wantApply) {
+				t.Errorf("Apply = %v, want %v", got, tt.wantApply)
+			}
+		})
+	}
+}
+
+func TestPlanTo_UnknownTarget(t *testing.T) {
+	if _, err := planTo("999", nil, []Registration{{Name: "001"}}); err == nil {
+		t.Error("expected error for unregistered target")
+	}
+}
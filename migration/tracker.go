@@ -1,10 +1,18 @@
 package migration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
 
 	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/internal/version"
+	"github.com/Grandbusta/jone/types"
 )
 
 // Tracker handles migration tracking in the database.
@@ -12,6 +20,8 @@ type Tracker struct {
 	db        *sql.DB
 	dialect   dialect.Dialect
 	tableName string
+	ctx       context.Context // nil = context.Background()
+	out       io.Writer       // Progress messages; nil = os.Stdout
 }
 
 // NewTracker creates a new migration tracker.
@@ -26,20 +36,117 @@ func NewTracker(db *sql.DB, d dialect.Dialect, tableName string) *Tracker {
 	}
 }
 
-// EnsureTable creates the migrations tracking table if it doesn't exist.
+// WithContext returns a copy of the tracker whose queries run under ctx.
+func (t *Tracker) WithContext(ctx context.Context) *Tracker {
+	t2 := *t
+	t2.ctx = ctx
+	return &t2
+}
+
+func (t *Tracker) context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+func (t *Tracker) stdout() io.Writer {
+	if t.out == nil {
+		return os.Stdout
+	}
+	return t.out
+}
+
+// AppliedMigration is a row of the migrations tracking table.
+type AppliedMigration struct {
+	Name      string
+	Batch     int
+	Checksum  string // Empty for migrations applied before checksums were recorded
+	AppliedAt string
+}
+
+// trackingColumns are columns added to the tracking table after its first release.
+// EnsureTable adds them to tables created by older versions.
+var trackingColumns = []*types.Column{
+	{Name: "checksum", DataType: "varchar", Length: 64},
+	{Name: "duration_ms", DataType: "int"},
+	{Name: "applied_by", DataType: "varchar", Length: 255},
+	{Name: "hostname", DataType: "varchar", Length: 255},
+	{Name: "jone_version", DataType: "varchar", Length: 32},
+}
+
+// tracking returns the SQL for the tracking columns and history table.
+func (t *Tracker) tracking() dialect.TrackingSQL {
+	return dialect.Tracking(t.dialect)
+}
+
+// logTable returns the name of the append-only history table.
+func (t *Tracker) logTable() string {
+	return t.tableName + "_log"
+}
+
+// EnsureTable creates the migrations tracking and history tables if they
+// don't exist and adds any columns missing from a tracking table created by an
+// older version.
 func (t *Tracker) EnsureTable() error {
 	sql := t.dialect.CreateMigrationsTableSQL(t.tableName)
-	_, err := t.db.Exec(sql)
+	_, err := t.db.ExecContext(t.context(), sql)
 	if err != nil {
 		return fmt.Errorf("failed to create migrations table '%s': %w", t.tableName, err)
 	}
+
+	if _, err := t.db.ExecContext(t.context(), t.tracking().CreateMigrationLogTableSQL(t.logTable())); err != nil {
+		return fmt.Errorf("failed to create migration history table '%s': %w", t.logTable(), err)
+	}
+
+	for _, col := range trackingColumns {
+		exists, err := t.hasColumn(col.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := t.db.ExecContext(t.context(), t.tracking().AddMigrationsColumnSQL(t.tableName, col)); err != nil {
+			return fmt.Errorf("adding column %s to migrations table '%s': %w", col.Name, t.tableName, err)
+		}
+	}
 	return nil
 }
 
+// hasColumn reports whether the tracking table has the named column.
+func (t *Tracker) hasColumn(name string) (bool, error) {
+	var count int
+	query := t.dialect.HasColumnSQL("", t.tableName, name)
+	if err := t.db.QueryRowContext(t.context(), query).Scan(&count); err != nil {
+		return false, fmt.Errorf("checking migrations table '%s' for column %s: %w", t.tableName, name, err)
+	}
+	return count > 0, nil
+}
+
+// Exists reports whether the migrations tracking table exists.
+func (t *Tracker) Exists() (bool, error) {
+	return t.tableExists(t.tableName)
+}
+
+// HistoryExists reports whether the migration history table exists.
+func (t *Tracker) HistoryExists() (bool, error) {
+	return t.tableExists(t.logTable())
+}
+
+func (t *Tracker) tableExists(name string) (bool, error) {
+	var count int
+	query := t.dialect.HasTableSQL("", name)
+	if err := t.db.QueryRowContext(t.context(), query).Scan(&count); err != nil {
+		return false, fmt.Errorf("checking for table '%s': %w", name, err)
+	}
+	return count > 0, nil
+}
+
 // GetApplied returns the list of applied migration names in order.
 func (t *Tracker) GetApplied() ([]string, error) {
 	sql := t.dialect.GetAppliedMigrationsSQL(t.tableName)
-	rows, err := t.db.Query(sql)
+	rows, err := t.db.QueryContext(t.context(), sql)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query migrations from '%s': %w", t.tableName, err)
 	}
@@ -56,11 +163,83 @@ func (t *Tracker) GetApplied() ([]string, error) {
 	return names, rows.Err()
 }
 
+// GetRecords returns the applied migrations with their batch and checksum, in order.
+func (t *Tracker) GetRecords() ([]AppliedMigration, error) {
+	query := t.tracking().GetMigrationRecordsSQL(t.tableName)
+	rows, err := t.db.QueryContext(t.context(), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations from '%s': %w", t.tableName, err)
+	}
+	defer rows.Close()
+
+	var records []AppliedMigration
+	for rows.Next() {
+		var rec AppliedMigration
+		var checksum, appliedAt sql.NullString
+		if err := rows.Scan(&rec.Name, &rec.Batch, &checksum, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scanning migration record: %w", err)
+		}
+		rec.Checksum, rec.AppliedAt = checksum.String, appliedAt.String
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// readRecords returns the applied migrations like GetRecords, but without
+// changing the tracking table, for runs that must not write. A table created
+// before checksums were recorded, which EnsureTable hasn't upgraded yet, is
+// read with the original tracking queries; its records have no checksum or
+// applied_at.
+func (t *Tracker) readRecords() ([]AppliedMigration, error) {
+	upgraded, err := t.hasColumn("checksum")
+	if err != nil {
+		return nil, err
+	}
+	if upgraded {
+		return t.GetRecords()
+	}
+
+	names, err := t.GetApplied()
+	if err != nil {
+		return nil, err
+	}
+	lastBatch, err := t.GetLastBatch()
+	if err != nil {
+		return nil, err
+	}
+	batches := make(map[string]int, len(names))
+	for batch := 1; batch <= lastBatch; batch++ {
+		inBatch, err := t.GetBatchMigrations(batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range inBatch {
+			batches[name] = batch
+		}
+	}
+
+	records := make([]AppliedMigration, len(names))
+	for i, name := range names {
+		records[i] = AppliedMigration{Name: name, Batch: batches[name]}
+	}
+	return records, nil
+}
+
+// UpdateChecksum replaces the stored checksum of an applied migration.
+func (t *Tracker) UpdateChecksum(name, checksum string) error {
+	sql := t.tracking().UpdateMigrationChecksumSQL(t.tableName)
+	_, err := t.db.ExecContext(t.context(), sql, nullable(checksum), name)
+	if err != nil {
+		return fmt.Errorf("updating checksum of %s: %w", name, err)
+	}
+	return nil
+}
+
 // GetLastBatch returns the highest batch number, or 0 if no migrations exist.
 func (t *Tracker) GetLastBatch() (int, error) {
 	sql := t.dialect.GetLastBatchSQL(t.tableName)
 	var batch int
-	err := t.db.QueryRow(sql).Scan(&batch)
+	err := t.db.QueryRowContext(t.context(), sql).Scan(&batch)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get last batch number from '%s': %w", t.tableName, err)
 	}
@@ -70,7 +249,7 @@ func (t *Tracker) GetLastBatch() (int, error) {
 // GetBatchMigrations returns migration names for a specific batch in reverse order.
 func (t *Tracker) GetBatchMigrations(batch int) ([]string, error) {
 	sql := t.dialect.GetMigrationsByBatchSQL(t.tableName)
-	rows, err := t.db.Query(sql, batch)
+	rows, err := t.db.QueryContext(t.context(), sql, batch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query batch %d migrations: %w", batch, err)
 	}
@@ -88,9 +267,11 @@ func (t *Tracker) GetBatchMigrations(batch int) ([]string, error) {
 }
 
 // RecordMigration inserts a record for a successfully run migration.
-func (t *Tracker) RecordMigration(name string, batch int) error {
-	sql := t.dialect.InsertMigrationSQL(t.tableName)
-	_, err := t.db.Exec(sql, name, batch)
+func (t *Tracker) RecordMigration(name string, batch int, checksum string, duration time.Duration) error {
+	sql := t.tracking().RecordMigrationSQL(t.tableName)
+	op := currentOperator()
+	_, err := t.db.ExecContext(t.context(), sql, name, batch, nullable(checksum),
+		duration.Milliseconds(), nullable(op.user), nullable(op.hostname), version.Version)
 	if err != nil {
 		return fmt.Errorf("recording migration %s: %w", name, err)
 	}
@@ -98,9 +279,11 @@ func (t *Tracker) RecordMigration(name string, batch int) error {
 }
 
 // RecordMigrationTx inserts a record using the provided transaction.
-func (t *Tracker) RecordMigrationTx(tx *sql.Tx, name string, batch int) error {
-	sql := t.dialect.InsertMigrationSQL(t.tableName)
-	_, err := tx.Exec(sql, name, batch)
+func (t *Tracker) RecordMigrationTx(tx *sql.Tx, name string, batch int, checksum string, duration time.Duration) error {
+	sql := t.tracking().RecordMigrationSQL(t.tableName)
+	op := currentOperator()
+	_, err := tx.ExecContext(t.context(), sql, name, batch, nullable(checksum),
+		duration.Milliseconds(), nullable(op.user), nullable(op.hostname), version.Version)
 	if err != nil {
 		return fmt.Errorf("recording migration %s: %w", name, err)
 	}
@@ -110,7 +293,7 @@ func (t *Tracker) RecordMigrationTx(tx *sql.Tx, name string, batch int) error {
 // RemoveMigration deletes a migration record.
 func (t *Tracker) RemoveMigration(name string) error {
 	sql := t.dialect.DeleteMigrationSQL(t.tableName)
-	_, err := t.db.Exec(sql, name)
+	_, err := t.db.ExecContext(t.context(), sql, name)
 	if err != nil {
 		return fmt.Errorf("removing migration %s: %w", name, err)
 	}
@@ -120,9 +303,136 @@ func (t *Tracker) RemoveMigration(name string) error {
 // RemoveMigrationTx deletes a migration record using the provided transaction.
 func (t *Tracker) RemoveMigrationTx(tx *sql.Tx, name string) error {
 	sql := t.dialect.DeleteMigrationSQL(t.tableName)
-	_, err := tx.Exec(sql, name)
+	_, err := tx.ExecContext(t.context(), sql, name)
 	if err != nil {
 		return fmt.Errorf("removing migration %s: %w", name, err)
 	}
 	return nil
 }
+
+// Directions and outcomes stored in the history table.
+const (
+	directionUp   = "up"
+	directionDown = "down"
+
+	statusSuccess  = "success"
+	statusFailed   = "failed"
+	statusBaseline = "baseline" // Marked as applied by migrate:baseline, without running Up
+	statusFake     = "fake"     // Marked by migrate:fake, without running Up or Down
+)
+
+// HistoryEntry is a row of the append-only migration history table.
+type HistoryEntry struct {
+	Name        string `json:"name"`
+	Direction   string `json:"direction"` // "up" or "down"
+	Batch       int    `json:"batch,omitempty"`
+	Status      string `json:"status"` // "success", "failed", "baseline" or "fake"
+	DurationMs  int64  `json:"duration_ms"`
+	AppliedBy   string `json:"applied_by,omitempty"`
+	Hostname    string `json:"hostname,omitempty"`
+	JoneVersion string `json:"jone_version,omitempty"`
+	Error       string `json:"error,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Log appends a history entry for a run of a migration. runErr is the error
+// that made it fail, or nil. batch 0 is stored as NULL.
+func (t *Tracker) Log(name, direction string, batch int, duration time.Duration, runErr error) error {
+	_, err := t.db.ExecContext(t.context(), t.tracking().InsertMigrationLogSQL(t.logTable()), t.logArgs(name, direction, batch, duration, runErr)...)
+	if err != nil {
+		return fmt.Errorf("logging migration %s: %w", name, err)
+	}
+	return nil
+}
+
+// LogTx appends a history entry using the provided transaction, so it is only
+// kept if the transaction commits.
+func (t *Tracker) LogTx(tx *sql.Tx, name, direction string, batch int, duration time.Duration, runErr error) error {
+	_, err := tx.ExecContext(t.context(), t.tracking().InsertMigrationLogSQL(t.logTable()), t.logArgs(name, direction, batch, duration, runErr)...)
+	if err != nil {
+		return fmt.Errorf("logging migration %s: %w", name, err)
+	}
+	return nil
+}
+
+// logMarked appends a history entry for a migration that was marked as applied,
+// or as not applied, without running it. status is statusBaseline or
+// statusFake.
+func (t *Tracker) logMarked(name, direction string, batch int, status string) error {
+	_, err := t.db.ExecContext(t.context(), t.tracking().InsertMigrationLogSQL(t.logTable()), t.entryArgs(name, direction, batch, status, 0, "")...)
+	if err != nil {
+		return fmt.Errorf("logging migration %s: %w", name, err)
+	}
+	return nil
+}
+
+func (t *Tracker) logArgs(name, direction string, batch int, duration time.Duration, runErr error) []any {
+	status, errText := statusSuccess, ""
+	if runErr != nil {
+		status, errText = statusFailed, runErr.Error()
+	}
+	return t.entryArgs(name, direction, batch, status, duration, errText)
+}
+
+func (t *Tracker) entryArgs(name, direction string, batch int, status string, duration time.Duration, errText string) []any {
+	var batchArg any
+	if batch > 0 {
+		batchArg = batch
+	}
+	op := currentOperator()
+	return []any{name, direction, batchArg, status, duration.Milliseconds(),
+		nullable(op.user), nullable(op.hostname), version.Version, nullable(errText)}
+}
+
+// GetHistory returns every history entry in the order they were logged.
+func (t *Tracker) GetHistory() ([]HistoryEntry, error) {
+	rows, err := t.db.QueryContext(t.context(), t.tracking().GetMigrationLogSQL(t.logTable()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migration history from '%s': %w", t.logTable(), err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var batch, duration sql.NullInt64
+		var appliedBy, hostname, joneVersion, errText, createdAt sql.NullString
+		if err := rows.Scan(&e.Name, &e.Direction, &batch, &e.Status, &duration,
+			&appliedBy, &hostname, &joneVersion, &errText, &createdAt); err != nil {
+			return nil, fmt.Errorf("scanning migration history: %w", err)
+		}
+		e.Batch = int(batch.Int64)
+		e.DurationMs = duration.Int64
+		e.AppliedBy, e.Hostname, e.JoneVersion = appliedBy.String, hostname.String, joneVersion.String
+		e.Error, e.CreatedAt = errText.String, createdAt.String
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// operator identifies who is running migrations, for the history columns.
+type operator struct {
+	user     string
+	hostname string
+}
+
+// currentOperator returns the OS user and hostname running this process.
+// Either is empty if it cannot be determined.
+var currentOperator = sync.OnceValue(func() operator {
+	var op operator
+	if u, err := user.Current(); err == nil {
+		op.user = u.Username
+	} else {
+		op.user = os.Getenv("USER")
+	}
+	op.hostname, _ = os.Hostname()
+	return op
+})
+
+// nullable stores an empty string as NULL.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// singleTransaction reports whether latest should run every pending migration
+// in one transaction, from Options.SingleTransaction or the config default.
+func (p RunParams) singleTransaction() bool {
+	return p.Options.SingleTransaction || p.Config.Migrations.SingleTransaction
+}
+
+// checkSingleTransaction refuses a single-transaction run on dialects whose
+// DDL commits implicitly, since a failure could not be rolled back.
+func (p RunParams) checkSingleTransaction() error {
+	if !p.singleTransaction() {
+		return nil
+	}
+	d := p.Schema.Dialect()
+	if tx, ok := d.(dialect.TransactionalDDL); ok && tx.SupportsTransactionalDDL() {
+		return nil
+	}
+	return fmt.Errorf("--single-transaction needs transactional DDL, which %s does not support: its DDL statements commit implicitly, so a failed run could not be rolled back. Run without it (and unset Migrations.SingleTransaction in jonefile.go)", d.Name())
+}
+
+// runInSingleTransaction applies pending as one transaction with their tracking
+// records. If any migration fails, none of them are applied.
+func runInSingleTransaction(p RunParams, tracker *Tracker, pending []Registration, batch int) error {
+	for _, reg := range pending {
+		if reg.Options.DisableTransaction {
+			return fmt.Errorf("migration '%s' disables transactions, so it can't run with --single-transaction", reg.Name)
+		}
+	}
+
+	s := p.Schema.WithContext(p.context())
+	tx, err := s.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op after a successful Commit
+
+	durations := make([]time.Duration, len(pending))
+	for i, reg := range pending {
+		start := time.Now()
+		if err := reg.up(s.WithTx(tx)); err != nil {
+			tx.Rollback()
+			p.logFailure(tracker, reg.Name, directionUp, batch, time.Since(start), err)
+			return fmt.Errorf("migration '%s' failed, no migrations in batch %d were applied: %w", reg.Name, batch, err)
+		}
+		duration := time.Since(start)
+		durations[i] = duration
+		if err := tracker.RecordMigrationTx(tx, reg.Name, batch, checksumOrEmpty(reg, p.Schema), duration); err != nil {
+			return fmt.Errorf("failed to record migration '%s': %w", reg.Name, err)
+		}
+		if err := tracker.LogTx(tx, reg.Name, directionUp, batch, duration, nil); err != nil {
+			return err
+		}
+		fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Ran: %s", reg.Name)))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch %d: %w", batch, err)
+	}
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Committed %d migration(s)", len(pending))))
+	for i, reg := range pending {
+		p.emit(EventMigrated, reg.Name, directionUp, batch, durations[i], nil)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package migration
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	"github.com/Grandbusta/jone/schema"
+)
+
+func TestCheckSingleTransaction(t *testing.T) {
+	tests := []struct {
+		client  string
+		option  bool
+		config  bool
+		wantErr bool
+	}{
+		{"postgresql", true, false, false},
+		{"sqlite", false, true, false},
+		{"mssql", true, false, false},
+		{"mysql", false, false, false},
+		{"mysql", true, false, true},
+		{"mysql", false, true, true},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{Client: tt.client, Migrations: config.Migrations{SingleTransaction: tt.config}}
+		s, err := schema.New(cfg)
+		if err != nil {
+			t.Fatalf("schema.New(%q) error: %v", tt.client, err)
+		}
+		p := RunParams{Config: cfg, Schema: s, Options: RunOptions{SingleTransaction: tt.option}}
+
+		err = p.checkSingleTransaction()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s (option %v, config %v): checkSingleTransaction() error = %v, wantErr %v",
+				tt.client, tt.option, tt.config, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRunLatest_SingleTransactionFailure(t *testing.T) {
+	s, db := newFakeDB(t)
+	regs := append(testRegistrations(), Registration{
+		Name: "003_tags",
+		Up:   func(s *schema.Schema) { s.CreateTable("tags", func(t *schema.Table) { t.Increments("id") }) },
+	})
+	regs[1].Up = func(s *schema.Schema) { s.Raw("FAIL") }
+	p := RunParams{Config: &config.Config{}, Registrations: regs, Schema: s, Options: RunOptions{SingleTransaction: true}, out: io.Discard}
+
+	if err := RunLatest(p); err == nil || !strings.Contains(err.Error(), "no migrations in batch 1 were applied") {
+		t.Fatalf("RunLatest() error = %v, want the batch to fail", err)
+	}
+	if got := db.appliedNames(); len(got) != 0 {
+		t.Errorf("tracking table = %v, want empty", got)
+	}
+	if len(db.state.executed) != 0 {
+		t.Errorf("executed = %q, want 001_users rolled back", db.state.executed)
+	}
+	// Only the failure is logged, outside the rolled back transaction
+	if want := []fakeLogEntry{{"002_posts", directionUp, statusFailed}}; !slices.Equal(db.state.history, want) {
+		t.Errorf("history = %+v, want %+v", db.state.history, want)
+	}
+}
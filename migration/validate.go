@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/Grandbusta/jone/internal/term"
+)
+
+// checksumDrift is an applied migration whose stored checksum doesn't match
+// the current source.
+type checksumDrift struct {
+	Name     string
+	Stored   string // Empty if no checksum was recorded
+	Current  string
+	Recorded bool // A checksum was stored when the migration was applied
+}
+
+// findChecksumDrift compares stored checksums of applied migrations with the
+// registered code. Migrations missing from the registry, or whose checksum
+// cannot be computed, are skipped.
+func findChecksumDrift(p RunParams, records []AppliedMigration) []checksumDrift {
+	regMap := make(map[string]Registration)
+	for _, reg := range p.Registrations {
+		regMap[reg.Name] = reg
+	}
+
+	var drift []checksumDrift
+	for _, rec := range records {
+		reg, ok := regMap[rec.Name]
+		if !ok {
+			continue
+		}
+		current := checksumOrEmpty(reg, p.Schema)
+		if current == "" || current == rec.Checksum {
+			continue
+		}
+		drift = append(drift, checksumDrift{
+			Name:     rec.Name,
+			Stored:   rec.Checksum,
+			Current:  current,
+			Recorded: rec.Checksum != "",
+		})
+	}
+	return drift
+}
+
+// RunValidate checks that applied migrations have not been edited since they ran.
+// With Options.Repair it stores the current checksums instead, after an
+// intentional change or to fill in checksums for migrations applied before
+// they were recorded.
+func RunValidate(p RunParams) error {
+	tracker := p.newTracker()
+
+	// Hold the migration lock for the whole run
+	unlock, err := p.lock(tracker)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := tracker.EnsureTable(); err != nil {
+		return err
+	}
+
+	records, err := tracker.GetRecords()
+	if err != nil {
+		return err
+	}
+
+	drift := findChecksumDrift(p, records)
+
+	if p.Options.Repair {
+		for _, d := range drift {
+			if err := tracker.UpdateChecksum(d.Name, d.Current); err != nil {
+				return err
+			}
+			fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("  ✓ Updated checksum: %s", d.Name)))
+		}
+		fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ %d checksum(s) updated", len(drift))))
+		return nil
+	}
+
+	changed := 0
+	for _, d := range drift {
+		if d.Recorded {
+			fmt.Fprintf(p.stdout(), "  %s  %s %s\n", term.RedText("✗"), d.Name, term.RedText("(changed since applied)"))
+			changed++
+		} else {
+			fmt.Fprintf(p.stdout(), "  %s  %s %s\n", term.YellowText("?"), d.Name, term.YellowText("(no checksum recorded)"))
+		}
+	}
+
+	if changed > 0 {
+		return fmt.Errorf("%d applied migration(s) changed since they ran. Restore them, or run migrate:validate --repair if the change is intentional", changed)
+	}
+	if len(drift) > 0 {
+		fmt.Fprintln(p.stdout(), term.YellowText(fmt.Sprintf("%d migration(s) have no checksum. Run migrate:validate --repair to record them", len(drift))))
+	}
+	fmt.Fprintln(p.stdout(), term.GreenText(fmt.Sprintf("✓ %d applied migration(s) validated", len(records))))
+	return nil
+}
@@ -0,0 +1,20 @@
+package schema
+
+import "fmt"
+
+// StatementError reports a SQL statement that failed while running a migration.
+type StatementError struct {
+	Kind string // Operation, e.g. "CREATE TABLE", "ALTER TABLE", "raw SQL"
+	SQL  string // The statement that failed
+	Err  error  // The error returned by the driver
+}
+
+// Error returns the operation, the driver error and the failing statement.
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("executing %s: %v\nstatement: %s", e.Kind, e.Err, e.SQL)
+}
+
+// Unwrap returns the underlying driver error.
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,44 @@
+package schema
+
+// Statement is a SQL statement generated by a schema operation.
+type Statement struct {
+	Kind      string // Operation, e.g. "CREATE TABLE", "ALTER TABLE", "raw SQL"
+	Table     string // Table the statement affects ("" for raw SQL)
+	Migration string // Migration that generated it, if set with SetMigration
+	SQL       string
+	Args      []any
+}
+
+// Recorder collects the statements generated by a Schema instead of running them.
+type Recorder struct {
+	statements       []Statement
+	migration        string
+	describeRebuilds bool
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Statements returns the recorded statements in order.
+func (r *Recorder) Statements() []Statement {
+	return r.statements
+}
+
+// SetMigration sets the migration name stamped on statements recorded after it.
+func (r *Recorder) SetMigration(name string) {
+	r.migration = name
+}
+
+// DescribeRebuilds makes alterations that need a dialect table rebuild record
+// a comment describing their actions instead of failing. The comment can't
+// rebuild the table; it only identifies the change, as checksums need.
+func (r *Recorder) DescribeRebuilds() {
+	r.describeRebuilds = true
+}
+
+func (r *Recorder) record(stmt Statement) {
+	stmt.Migration = r.migration
+	r.statements = append(r.statements, stmt)
+}
@@ -1,60 +1,110 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/Grandbusta/jone/config"
 	"github.com/Grandbusta/jone/dialect"
+	"github.com/Grandbusta/jone/types"
 )
 
 // Execer is an interface for executing SQL (both *sql.DB and *sql.Tx).
 type Execer interface {
-	Exec(query string, args ...any) (sql.Result, error)
-	Query(query string, args ...any) (*sql.Rows, error)
-	QueryRow(query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// contextQueryer adapts an Execer to dialect.Queryer, running queries under ctx.
+type contextQueryer struct {
+	ctx    context.Context
+	execer Execer
+}
+
+func (q contextQueryer) Query(query string, args ...any) (*sql.Rows, error) {
+	return q.execer.QueryContext(q.ctx, query, args...)
 }
 
 // Schema provides methods for database schema operations.
+//
+// Schema methods do not return errors, so migrations read like a list of
+// operations. Instead, the first failing statement is recorded and every later
+// operation on the same Schema (or one derived from it with WithSchema) is
+// skipped; the runner checks Err after Up/Down returns and rolls back.
 type Schema struct {
-	dialect dialect.Dialect
-	db      *sql.DB // original connection (for Begin, Close)
-	execer  Execer  // current executor (db or tx)
-	config  *config.Config
-	schema  string // current schema context
+	dialect  dialect.Dialect
+	db       *sql.DB // original connection (for Begin, Close)
+	execer   Execer  // current executor (db or tx)
+	config   *config.Config
+	schema   string          // current schema context
+	ctx      context.Context // context for statements (nil = context.Background())
+	state    *execState      // shared with schemas derived via WithSchema
+	recorder *Recorder       // if set, statements are recorded instead of executed
 }
 
-// fatal logs the error and exits. Used for unrecoverable schema errors during migrations.
-func fatal(format string, args ...any) {
-	err := fmt.Errorf("ERROR: "+format, args...)
-	fmt.Println(err)
-	// log.Printf("ERROR: "+format, args...)
-	os.Exit(1)
+// execState holds the first error hit by a Schema and the schemas derived from it.
+type execState struct {
+	err error
 }
 
 // New creates a new Schema with the given config.
 // It determines the dialect from the config and can optionally connect to the database.
-func New(cfg *config.Config) *Schema {
-	d := dialect.GetDialect(cfg.Client)
+// It returns an error if cfg.Client does not name a registered dialect.
+func New(cfg *config.Config) (*Schema, error) {
+	d, err := dialect.GetDialect(cfg.Client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Client in jonefile.go: %w", err)
+	}
 	return &Schema{
 		dialect: d,
 		config:  cfg,
-	}
+		state:   &execState{},
+	}, nil
 }
 
 // WithSchema returns a new Schema that operates on the specified schema.
 func (s *Schema) WithSchema(schemaName string) *Schema {
 	return &Schema{
-		dialect: s.dialect,
-		db:      s.db,
-		execer:  s.execer,
-		config:  s.config,
-		schema:  schemaName,
+		dialect:  s.dialect,
+		db:       s.db,
+		execer:   s.execer,
+		config:   s.config,
+		schema:   schemaName,
+		ctx:      s.ctx,
+		state:    s.state,
+		recorder: s.recorder,
 	}
 }
 
+// WithContext returns a new Schema whose statements run under ctx.
+// Cancelling ctx aborts the running statement, and a transaction begun with
+// BeginTx is rolled back by database/sql. Errors are shared with s.
+func (s *Schema) WithContext(ctx context.Context) *Schema {
+	return &Schema{
+		dialect:  s.dialect,
+		db:       s.db,
+		execer:   s.execer,
+		config:   s.config,
+		schema:   s.schema,
+		ctx:      ctx,
+		state:    s.state,
+		recorder: s.recorder,
+	}
+}
+
+// Context returns the Schema's context, or context.Background() if none was set.
+func (s *Schema) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
 // WithTx returns a new Schema that uses the given transaction.
+// The returned Schema starts with no recorded error.
 func (s *Schema) WithTx(tx *sql.Tx) *Schema {
 	return &Schema{
 		dialect: s.dialect,
@@ -62,15 +112,79 @@ func (s *Schema) WithTx(tx *sql.Tx) *Schema {
 		execer:  tx,
 		config:  s.config,
 		schema:  s.schema,
+		ctx:     s.ctx,
+		state:   &execState{},
+	}
+}
+
+// WithDB returns a new Schema that runs statements directly on the connection,
+// outside any transaction. The returned Schema starts with no recorded error.
+func (s *Schema) WithDB() *Schema {
+	return &Schema{
+		dialect: s.dialect,
+		db:      s.db,
+		execer:  s.db,
+		config:  s.config,
+		schema:  s.schema,
+		ctx:     s.ctx,
+		state:   &execState{},
+	}
+}
+
+// WithRecorder returns a new Schema that records the statements it generates
+// in r instead of running them. It has no connection, so HasTable and HasColumn
+// report false and alterations that need a dialect table rebuild fail unless
+// r describes them (see Recorder.DescribeRebuilds).
+func (s *Schema) WithRecorder(r *Recorder) *Schema {
+	return &Schema{
+		dialect:  s.dialect,
+		config:   s.config,
+		schema:   s.schema,
+		ctx:      s.ctx,
+		state:    &execState{},
+		recorder: r,
+	}
+}
+
+// Err returns the first error recorded by a schema operation, or nil.
+// Once set, later operations are skipped.
+func (s *Schema) Err() error {
+	return s.state.err
+}
+
+// fail records err unless an earlier error has already been recorded.
+func (s *Schema) fail(err error) {
+	if s.state.err == nil {
+		s.state.err = err
+	}
+}
+
+// exec runs a single statement, records it, or prints it when there is no
+// connection. kind names the operation in error messages and table the table it
+// affects ("" for raw SQL). Nothing runs after an error.
+func (s *Schema) exec(kind, table, sqlStmt string, args ...any) {
+	if s.state.err != nil {
+		return
+	}
+	if s.recorder != nil {
+		s.recorder.record(Statement{Kind: kind, Table: table, SQL: sqlStmt, Args: args})
+		return
+	}
+	if s.execer == nil {
+		fmt.Println(sqlStmt)
+		return
+	}
+	if _, err := s.execer.ExecContext(s.Context(), sqlStmt, args...); err != nil {
+		s.fail(&StatementError{Kind: kind, SQL: sqlStmt, Err: err})
 	}
 }
 
-// BeginTx starts a new transaction and returns it.
+// BeginTx starts a new transaction bound to the Schema's context and returns it.
 func (s *Schema) BeginTx() (*sql.Tx, error) {
 	if s.db == nil {
 		return nil, fmt.Errorf("no database connection")
 	}
-	return s.db.Begin()
+	return s.db.BeginTx(s.Context(), nil)
 }
 
 // SchemaName returns the current schema name (empty = default).
@@ -104,7 +218,7 @@ func (s *Schema) Open() error {
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w. Check your connection settings in jonefile.go", err)
 	}
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(s.Context()); err != nil {
 		return fmt.Errorf("cannot connect to database: %w. Verify connection settings in jonefile.go", err)
 	}
 
@@ -139,125 +253,106 @@ func (s *Schema) Close() error {
 // Raw executes a raw SQL statement with optional parameters.
 // Use this for custom DDL, data migrations, or database-specific features.
 func (s *Schema) Raw(sqlStmt string, args ...any) {
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt, args...)
-		if err != nil {
-			fatal("executing raw SQL: %v", err)
-		}
-	} else {
-		fmt.Println(sqlStmt)
-	}
+	s.exec("raw SQL", "", sqlStmt, args...)
 }
 
+// Table alters an existing table using the builder function.
 func (s *Schema) Table(name string, builder func(t *Table)) {
+	if s.state.err != nil {
+		return
+	}
 	t := NewTable(name)
 	t.Schema = s.schema // Set schema context
 	builder(t)
 
 	// Generate SQL for each action
-	statements := s.dialect.AlterTableSQL(s.schema, name, t.Actions)
+	statements, err := s.alterTableStatements(name, t.Actions)
+	if err != nil {
+		s.fail(err)
+		return
+	}
 
 	for _, sqlStmt := range statements {
-		if s.execer != nil {
-			_, err := s.execer.Exec(sqlStmt)
+		s.exec("ALTER TABLE", name, sqlStmt)
+	}
+}
+
+// alterTableStatements returns the statements that apply actions to the named table.
+// Dialects implementing dialect.TableRebuilder recreate the table for actions their
+// ALTER TABLE cannot express. That needs the current table definition, so without
+// a connection to read it from, such as when statements are recorded, it fails.
+func (s *Schema) alterTableStatements(name string, actions []*types.TableAction) ([]string, error) {
+	rebuilder, ok := s.dialect.(dialect.TableRebuilder)
+	if !ok || !rebuilder.NeedsRebuild(actions) {
+		return s.dialect.AlterTableSQL(s.schema, name, actions), nil
+	}
+	if s.execer == nil {
+		if s.recorder != nil && s.recorder.describeRebuilds {
+			desc, err := json.Marshal(actions)
 			if err != nil {
-				fatal("executing ALTER TABLE: %v", err)
+				return nil, fmt.Errorf("describing rebuild of table %s: %w", name, err)
 			}
-		} else {
-			fmt.Println(sqlStmt)
+			return []string{fmt.Sprintf("-- rebuild table %s: %s", s.dialect.QualifyTable(s.schema, name), desc)}, nil
 		}
+		return nil, fmt.Errorf("altering table %s needs a table rebuild, which reads the current table from the database; it can't be planned without a connection", name)
+	}
+
+	current, err := rebuilder.IntrospectTable(contextQueryer{s.Context(), s.execer}, s.schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading table %s for rebuild: %w", name, err)
 	}
+	return rebuilder.RebuildTableSQL(current, actions), nil
 }
 
 // CreateTable creates a new table with the given name using the builder function.
 func (s *Schema) CreateTable(name string, builder func(t *Table)) {
+	if s.state.err != nil {
+		return
+	}
 	t := NewTable(name)
 	t.Schema = s.schema // Set schema context
 	builder(t)
 
-	sqlStmt := s.dialect.CreateTableSQL(t.Table)
+	s.exec("CREATE TABLE", name, s.dialect.CreateTableSQL(t.Table))
 
-	// Execute if we have a database connection
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt)
-		if err != nil {
-			fatal("executing CREATE TABLE: %v", err)
-		}
-
-		// Execute COMMENT ON COLUMN for columns with comments (PostgreSQL needs separate statement)
-		qualifiedTable := s.dialect.QualifyTable(s.schema, name)
-		for _, col := range t.Columns {
-			if col.Comment != "" {
-				commentSQL := s.dialect.CommentColumnSQL(qualifiedTable, col.Name, col.Comment)
-				if _, err := s.execer.Exec(commentSQL); err != nil {
-					fatal("executing COMMENT ON COLUMN: %v", err)
-				}
+	// Execute COMMENT ON COLUMN for columns with comments (PostgreSQL needs separate statement)
+	qualifiedTable := s.dialect.QualifyTable(s.schema, name)
+	for _, col := range t.Columns {
+		if col.Comment != "" {
+			commentSQL := s.dialect.CommentColumnSQL(qualifiedTable, col.Name, col.Comment)
+			if commentSQL == "" {
+				continue // Dialect stores comments inline or not at all
 			}
+			s.exec("COMMENT ON COLUMN", name, commentSQL)
 		}
-	} else {
-		fmt.Println(sqlStmt)
 	}
 }
 
 // CreateTableIfNotExists creates a new table if it doesn't already exist.
 func (s *Schema) CreateTableIfNotExists(name string, builder func(t *Table)) {
+	if s.state.err != nil {
+		return
+	}
 	t := NewTable(name)
 	t.Schema = s.schema // Set schema context
 	builder(t)
 
-	sqlStmt := s.dialect.CreateTableIfNotExistsSQL(t.Table)
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt)
-		if err != nil {
-			fatal("executing CREATE TABLE IF NOT EXISTS: %v", err)
-		}
-	} else {
-		fmt.Println(sqlStmt)
-	}
+	s.exec("CREATE TABLE IF NOT EXISTS", name, s.dialect.CreateTableIfNotExistsSQL(t.Table))
 }
 
 // DropTable drops a table by name.
 func (s *Schema) DropTable(name string) {
-	sqlStmt := s.dialect.DropTableSQL(s.schema, name)
-
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt)
-		if err != nil {
-			fatal("executing DROP TABLE: %v", err)
-		}
-	} else {
-		fmt.Println(sqlStmt)
-	}
+	s.exec("DROP TABLE", name, s.dialect.DropTableSQL(s.schema, name))
 }
 
 // DropTableIfExists drops a table if it exists.
 func (s *Schema) DropTableIfExists(name string) {
-	sqlStmt := s.dialect.DropTableIfExistsSQL(s.schema, name)
-
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt)
-		if err != nil {
-			fatal("executing DROP TABLE IF EXISTS: %v", err)
-		}
-	} else {
-		fmt.Println(sqlStmt)
-	}
+	s.exec("DROP TABLE IF EXISTS", name, s.dialect.DropTableIfExistsSQL(s.schema, name))
 }
 
 // RenameTable renames a table from oldName to newName.
 func (s *Schema) RenameTable(oldName, newName string) {
-	sqlStmt := fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
-		s.dialect.QualifyTable(s.schema, oldName),
-		s.dialect.QuoteIdentifier(newName))
-
-	if s.execer != nil {
-		_, err := s.execer.Exec(sqlStmt)
-		if err != nil {
-			fatal("executing RENAME TABLE: %v", err)
-		}
-	} else {
-		fmt.Println(sqlStmt)
-	}
+	s.exec("RENAME TABLE", oldName, dialect.RenameTableSQL(s.dialect, s.schema, oldName, newName))
 }
 
 // HasTable checks if a table exists.
@@ -267,7 +362,7 @@ func (s *Schema) HasTable(name string) bool {
 	}
 	sql := s.dialect.HasTableSQL(s.schema, name)
 	var count int
-	if err := s.execer.QueryRow(sql).Scan(&count); err != nil {
+	if err := s.execer.QueryRowContext(s.Context(), sql).Scan(&count); err != nil {
 		return false
 	}
 	return count > 0
@@ -280,7 +375,7 @@ func (s *Schema) HasColumn(table, column string) bool {
 	}
 	sql := s.dialect.HasColumnSQL(s.schema, table, column)
 	var count int
-	if err := s.execer.QueryRow(sql).Scan(&count); err != nil {
+	if err := s.execer.QueryRowContext(s.Context(), sql).Scan(&count); err != nil {
 		return false
 	}
 	return count > 0
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+)
+
+// failingExecer records executed statements and fails the one matching failOn.
+type failingExecer struct {
+	failOn   string
+	executed []string
+}
+
+func (e *failingExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	e.executed = append(e.executed, query)
+	if query == e.failOn {
+		return nil, errors.New("relation already exists")
+	}
+	return nil, nil
+}
+
+func (e *failingExecer) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (e *failingExecer) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return nil
+}
+
+func TestSchema_CollectsFirstError(t *testing.T) {
+	s, err := New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	e := &failingExecer{failOn: `DROP TABLE "users";`}
+	s.execer = e
+
+	s.Raw("SELECT 1")
+	s.DropTable("users")
+	s.WithSchema("audit").DropTable("logs")
+	s.RenameTable("a", "b")
+
+	var stmtErr *StatementError
+	if !errors.As(s.Err(), &stmtErr) {
+		t.Fatalf("Err() = %v, want *StatementError", s.Err())
+	}
+	if stmtErr.Kind != "DROP TABLE" || stmtErr.SQL != `DROP TABLE "users";` {
+		t.Errorf("StatementError = %+v", stmtErr)
+	}
+	if len(e.executed) != 2 {
+		t.Errorf("executed %d statements after the failure, want none: %v", len(e.executed)-2, e.executed)
+	}
+}
+
+func TestSchema_WithTxResetsError(t *testing.T) {
+	s, err := New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	s.execer = &failingExecer{failOn: "SELECT 1"}
+	s.Raw("SELECT 1")
+	if s.Err() == nil {
+		t.Fatal("expected an error")
+	}
+
+	if err := s.WithTx(nil).Err(); err != nil {
+		t.Errorf("WithTx().Err() = %v, want nil", err)
+	}
+}
+
+func TestSchema_WithContextCancels(t *testing.T) {
+	s, err := New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	e := &failingExecer{}
+	s.execer = e
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.WithContext(ctx).Raw("SELECT 1")
+
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", s.Err())
+	}
+	if len(e.executed) != 0 {
+		t.Errorf("executed %v after cancellation", e.executed)
+	}
+}
+
+func TestSchema_WithRecorder(t *testing.T) {
+	s, err := New(&config.Config{Client: "postgresql"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	e := &failingExecer{}
+	s.execer = e
+
+	rec := NewRecorder()
+	rec.SetMigration("001_users")
+	rs := s.WithRecorder(rec)
+	rs.CreateTable("users", func(t *Table) {
+		t.Increments("id")
+		t.String("email").Comment("login")
+	})
+	rs.Raw("UPDATE users SET email = $1", "x")
+
+	stmts := rec.Statements()
+	if len(stmts) != 3 {
+		t.Fatalf("recorded %d statements, want 3: %v", len(stmts), stmts)
+	}
+	if stmts[0].Kind != "CREATE TABLE" || stmts[1].Kind != "COMMENT ON COLUMN" {
+		t.Errorf("kinds = %q, %q", stmts[0].Kind, stmts[1].Kind)
+	}
+	if stmts[1].Table != "users" || stmts[2].Table != "" {
+		t.Errorf("tables = %q, %q", stmts[1].Table, stmts[2].Table)
+	}
+	if stmts[2].Migration != "001_users" {
+		t.Errorf("migration = %q, want 001_users", stmts[2].Migration)
+	}
+	if len(stmts[2].Args) != 1 || stmts[2].Args[0] != "x" {
+		t.Errorf("raw args = %v", stmts[2].Args)
+	}
+	if len(e.executed) != 0 {
+		t.Errorf("recording schema executed %v", e.executed)
+	}
+}
+
+func TestSchema_RebuildWithoutConnection(t *testing.T) {
+	s, err := New(&config.Config{Client: "sqlite3"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	rec := NewRecorder()
+	rs := s.WithRecorder(rec)
+
+	rs.Table("users", func(t *Table) { t.String("email") })
+	if err := rs.Err(); err != nil {
+		t.Fatalf("adding a column: Err() = %v, want nil", err)
+	}
+	rs.Table("users", func(t *Table) { t.DropNullable("email") })
+	if err := rs.Err(); err == nil || !strings.Contains(err.Error(), "table rebuild") {
+		t.Errorf("Err() = %v, want a table rebuild error", err)
+	}
+	if len(rec.Statements()) != 1 {
+		t.Errorf("recorded %v, want only the added column", rec.Statements())
+	}
+}
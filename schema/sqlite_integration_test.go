@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Grandbusta/jone/config"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openSQLite returns a Schema connected to a new SQLite database file. It
+// skips the test when the driver can't open one, as when cgo is disabled.
+func openSQLite(t *testing.T) *Schema {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Skipf("sqlite3 driver unavailable: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Skipf("sqlite3 driver unavailable: %v", err)
+	}
+
+	s, err := New(&config.Config{Client: "sqlite3"})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	s.SetDB(db)
+	return s
+}
+
+func TestSQLite_RebuildTable(t *testing.T) {
+	s := openSQLite(t)
+	db := s.DB()
+
+	s.Raw("CREATE TABLE parents (id INTEGER PRIMARY KEY)")
+	// parent_id references the parent's primary key without naming it
+	s.Raw("CREATE TABLE children (id INTEGER PRIMARY KEY, name TEXT, parent_id INTEGER REFERENCES parents ON DELETE CASCADE)")
+	s.Raw("CREATE INDEX children_name_index ON children (name)")
+	s.Raw("INSERT INTO parents (id) VALUES (1)")
+	s.Raw("INSERT INTO children (id, name, parent_id) VALUES (1, 'a', 1), (2, 'b', 1)")
+	if err := s.Err(); err != nil {
+		t.Fatalf("setup error: %v", err)
+	}
+
+	s.Table("children", func(t *Table) {
+		t.DropNullable("name")
+		t.SetDefault("name", "unnamed")
+	})
+	if err := s.Err(); err != nil {
+		t.Fatalf("Table() error: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM children WHERE parent_id = 1").Scan(&count); err != nil || count != 2 {
+		t.Errorf("children after rebuild = %d, %v; want both rows kept", count, err)
+	}
+
+	var notNull int
+	var dflt sql.NullString
+	err := db.QueryRow("SELECT \"notnull\", dflt_value FROM pragma_table_info('children') WHERE name = 'name'").Scan(&notNull, &dflt)
+	if err != nil || notNull != 1 || dflt.String != "'unnamed'" {
+		t.Errorf("name column = notnull %d, default %q, %v; want NOT NULL DEFAULT 'unnamed'", notNull, dflt.String, err)
+	}
+
+	var to, onDelete string
+	err = db.QueryRow("SELECT \"to\", on_delete FROM pragma_foreign_key_list('children')").Scan(&to, &onDelete)
+	if err != nil || to != "id" || onDelete != "CASCADE" {
+		t.Errorf("foreign key = %q ON DELETE %q, %v; want parents.id ON DELETE CASCADE", to, onDelete, err)
+	}
+
+	var index string
+	if err := db.QueryRow("SELECT name FROM pragma_index_list('children') WHERE name = 'children_name_index'").Scan(&index); err != nil {
+		t.Errorf("index after rebuild: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO children (id) VALUES (3)"); err != nil {
+		t.Errorf("insert using the new default: %v", err)
+	}
+}
@@ -74,8 +74,10 @@ type Column struct {
 
 // Table represents a database table definition.
 type Table struct {
-	Name    string
-	Schema  string // Database schema (e.g., "public", "app")
-	Columns []*Column
-	Actions []*TableAction
+	Name        string
+	Schema      string // Database schema (e.g., "public", "app")
+	Columns     []*Column
+	Actions     []*TableAction
+	Indexes     []*Index      // Existing indexes (populated by table introspection)
+	ForeignKeys []*ForeignKey // Table-level foreign keys (populated by table introspection)
 }